@@ -0,0 +1,45 @@
+package payjpv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// normalizeAmountFields rewrites the named top-level fields of a JSON object from numeric
+// strings (e.g. "1000") to JSON numbers, so amounts decode into int fields even when a proxy
+// or API quirk returns them quoted. Other fields and non-object input are left untouched.
+func normalizeAmountFields(b []byte, names ...string) ([]byte, error) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return b, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, name := range names {
+		raw, ok := fields[name]
+		if !ok || len(raw) == 0 || raw[0] != '"' {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q for field %q: %w", s, name, err)
+		}
+		fields[name] = json.RawMessage(strconv.FormatInt(n, 10))
+		changed = true
+	}
+	if !changed {
+		return b, nil
+	}
+	return json.Marshal(fields)
+}
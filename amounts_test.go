@@ -0,0 +1,54 @@
+package payjpv2
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmountDecoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		amount   int
+		received int
+	}{
+		{"numbers", `{"id":"pfw_1","amount":1000,"amount_received":500,"currency":"jpy","status":"succeeded"}`, 1000, 500},
+		{"strings", `{"id":"pfw_1","amount":"1000","amount_received":"500","currency":"jpy","status":"succeeded"}`, 1000, 500},
+		{"mixed", `{"id":"pfw_1","amount":"1000","amount_received":500,"currency":"jpy","status":"succeeded"}`, 1000, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var flow PaymentFlowResponse
+			if err := json.Unmarshal([]byte(tt.body), &flow); err != nil {
+				t.Fatalf("Failed to decode payment flow: %v", err)
+			}
+			if flow.Amount != tt.amount {
+				t.Errorf("Amount = %d, want %d", flow.Amount, tt.amount)
+			}
+			if flow.AmountReceived == nil || *flow.AmountReceived != tt.received {
+				t.Errorf("AmountReceived = %v, want %d", flow.AmountReceived, tt.received)
+			}
+			if flow.Id != "pfw_1" || flow.Currency != "jpy" || flow.Status != PaymentFlowStatusSucceeded {
+				t.Errorf("Other fields not decoded: %+v", flow)
+			}
+		})
+	}
+
+	t.Run("null amount stays nil", func(t *testing.T) {
+		var flow PaymentFlowResponse
+		if err := json.Unmarshal([]byte(`{"amount":"1000","amount_received":null}`), &flow); err != nil {
+			t.Fatalf("Failed to decode payment flow: %v", err)
+		}
+		if flow.AmountReceived != nil {
+			t.Errorf("Expected nil AmountReceived, got: %d", *flow.AmountReceived)
+		}
+	})
+
+	t.Run("rejects non-numeric string", func(t *testing.T) {
+		var refund PaymentRefundResponse
+		if err := json.Unmarshal([]byte(`{"amount":"abc"}`), &refund); err == nil {
+			t.Error("Expected error for non-numeric amount, got nil")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package payjpv2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BatchItemError is the failure of a single item in a batch operation.
+type BatchItemError struct {
+	// ID identifies the failed item, usually the resource ID
+	ID string
+	// Err is the error returned for the item
+	Err error
+}
+
+// Error implements the error interface for BatchItemError.
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("%s: %v", e.ID, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError aggregates the per-item failures of a batch operation.
+// It supports errors.Is and errors.As against the individual item errors,
+// so an *APIError for any failed item can be extracted directly.
+type BatchError struct {
+	// Errors are the item failures, ordered by ID
+	Errors []*BatchItemError
+}
+
+// NewBatchError builds a BatchError from per-item errors keyed by ID.
+// Nil errors are skipped, and nil is returned when no item failed.
+func NewBatchError(errs map[string]error) error {
+	batchErr := &BatchError{}
+	for id, err := range errs {
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &BatchItemError{ID: id, Err: err})
+		}
+	}
+	if len(batchErr.Errors) == 0 {
+		return nil
+	}
+	sort.Slice(batchErr.Errors, func(i, j int) bool {
+		return batchErr.Errors[i].ID < batchErr.Errors[j].ID
+	})
+	return batchErr
+}
+
+// Error implements the error interface for BatchError.
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, itemErr := range e.Errors {
+		msgs[i] = itemErr.Error()
+	}
+	return fmt.Sprintf("%d batch item(s) failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the item errors.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, itemErr := range e.Errors {
+		errs[i] = itemErr
+	}
+	return errs
+}
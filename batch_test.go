@@ -0,0 +1,66 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBatchError(t *testing.T) {
+	t.Run("returns nil when no item failed", func(t *testing.T) {
+		if err := NewBatchError(map[string]error{"evnt_1": nil}); err != nil {
+			t.Errorf("Expected nil, got: %v", err)
+		}
+		if err := NewBatchError(nil); err != nil {
+			t.Errorf("Expected nil for nil map, got: %v", err)
+		}
+	})
+
+	t.Run("errors.As and errors.Is reach item errors", func(t *testing.T) {
+		notFound := &APIError{StatusCode: 404}
+		err := NewBatchError(map[string]error{
+			"evnt_2": notFound,
+			"evnt_1": context.DeadlineExceeded,
+			"evnt_3": nil,
+		})
+		if err == nil {
+			t.Fatal("Expected BatchError, got nil")
+		}
+
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("Expected *BatchError, got: %T", err)
+		}
+		if len(batchErr.Errors) != 2 {
+			t.Fatalf("Expected 2 item errors, got: %d", len(batchErr.Errors))
+		}
+		if batchErr.Errors[0].ID != "evnt_1" || batchErr.Errors[1].ID != "evnt_2" {
+			t.Errorf("Expected item errors ordered by ID, got: %s, %s", batchErr.Errors[0].ID, batchErr.Errors[1].ID)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatal("Expected errors.As to find *APIError")
+		}
+		if !apiErr.IsNotFound() {
+			t.Errorf("Expected 404 APIError, got status: %d", apiErr.StatusCode)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Error("Expected errors.Is to match context.DeadlineExceeded")
+		}
+		if !errors.Is(err, notFound) {
+			t.Error("Expected errors.Is to match the item APIError")
+		}
+	})
+
+	t.Run("Error() lists every item", func(t *testing.T) {
+		err := NewBatchError(map[string]error{
+			"evnt_1": errors.New("boom"),
+			"evnt_2": &APIError{StatusCode: 404},
+		})
+		expected := "2 batch item(s) failed: evnt_1: boom; evnt_2: PAY.JP API error 404"
+		if err.Error() != expected {
+			t.Errorf("Expected error message: %s, got: %s", expected, err.Error())
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package payjpv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// matchOperation reports whether req is the operation "METHOD /v2/path", where a * path segment
+// matches any one segment. Anything before /v2/ in the request path, such as the path of a
+// custom base URL, is ignored.
+func matchOperation(req *http.Request, operation string) bool {
+	method, pattern, _ := strings.Cut(operation, " ")
+	if method != req.Method {
+		return false
+	}
+	path := req.URL.Path
+	if i := strings.Index(path, "/v2/"); i >= 0 {
+		path = path[i:]
+	}
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if segment != "*" && segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// rewriteJSONBody passes the fields of req's JSON object body to edit, and replaces the body with
+// the edited fields if edit reports a change. Requests without a body or with a body that is not
+// a JSON object are left unchanged.
+func rewriteJSONBody(req *http.Request, edit func(fields map[string]json.RawMessage) bool) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil && fields != nil && edit(fields) {
+		if edited, err := json.Marshal(fields); err == nil {
+			body = edited
+		}
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package payjpv2
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMatchOperation(t *testing.T) {
+	tests := []struct {
+		method, url, operation string
+		expected               bool
+	}{
+		{"POST", "https://api.pay.jp/v2/customers", "POST /v2/customers", true},
+		{"POST", "https://api.pay.jp/v2/customers/cus_1", "POST /v2/customers/*", true},
+		{"POST", "https://gateway.example.com/payjp/v2/customers", "POST /v2/customers", true},
+		{"GET", "https://api.pay.jp/v2/customers", "POST /v2/customers", false},
+		{"POST", "https://api.pay.jp/v2/customers/cus_1/payment_methods", "POST /v2/customers/*", false},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if got := matchOperation(req, tt.operation); got != tt.expected {
+			t.Errorf("matchOperation(%s %s, %q) incorrect. Got: %v, Expected: %v", tt.method, tt.url, tt.operation, got, tt.expected)
+		}
+	}
+}
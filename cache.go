@@ -0,0 +1,193 @@
+package payjpv2
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores cached GET responses for WithCache.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for key, if any
+	Get(key string) ([]byte, bool)
+	// Set stores value for key
+	Set(key string, value []byte)
+}
+
+// defaultMemoryCacheEntries is the number of entries kept by NewMemoryCache
+const defaultMemoryCacheEntries = 1000
+
+// memoryCache is the in-memory Cache returned by NewMemoryCache. It evicts the least recently
+// used entry when full, so memory stays bounded however many distinct URLs are requested.
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+// memoryCacheEntry is an element of memoryCache.order
+type memoryCacheEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryCache returns a Cache that keeps up to 1000 entries in memory, evicting the least
+// recently used entry when full.
+func NewMemoryCache() Cache {
+	return NewMemoryCacheWithLimit(defaultMemoryCacheEntries)
+}
+
+// NewMemoryCacheWithLimit returns a Cache that keeps up to maxEntries entries in memory, evicting
+// the least recently used entry when full. A maxEntries below 1 keeps a single entry.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithCache(payjpv2.NewMemoryCacheWithLimit(10000), time.Minute),
+//	)
+func NewMemoryCacheWithLimit(maxEntries int) Cache {
+	return &memoryCache{
+		maxEntries: max(maxEntries, 1),
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (m *memoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(elem)
+	return elem.Value.(*memoryCacheEntry).value, true
+}
+
+// Set implements Cache.
+func (m *memoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if elem, ok := m.entries[key]; ok {
+		elem.Value.(*memoryCacheEntry).value = value
+		m.order.MoveToFront(elem)
+		return
+	}
+	m.entries[key] = m.order.PushFront(&memoryCacheEntry{key: key, value: value})
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// responseCache serves GET requests from a Cache
+type responseCache struct {
+	cache Cache
+	ttl   time.Duration
+}
+
+// cachedResponse is the value stored in the Cache for a response
+type cachedResponse struct {
+	ExpiresAt  time.Time   `json:"expires_at"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// WithCache returns a ClientOption that serves GET requests from c for ttl after a successful
+// response. Entries are keyed by URL and API key, and responses with Cache-Control: no-store
+// are not cached. Other methods always reach the API. Expiry follows the client's Clock, and
+// ttl must be positive.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithCache(payjpv2.NewMemoryCache(), time.Minute),
+//	)
+func WithCache(c Cache, ttl time.Duration) ClientOption {
+	return func(client *Client) error {
+		if c == nil {
+			return errors.New("cache cannot be nil")
+		}
+		if ttl <= 0 {
+			return errors.New("cache ttl must be positive")
+		}
+		sdkDoerFor(client).cache = &responseCache{cache: c, ttl: ttl}
+		return nil
+	}
+}
+
+// cacheKey returns the cache key of req; the API key is hashed rather than stored
+func cacheKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.URL.String() + " " + hex.EncodeToString(auth[:])
+}
+
+// do serves req from the cache, or sends it with next and caches a successful response,
+// using clock for expiry
+func (rc *responseCache) do(req *http.Request, clock Clock, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return next(req)
+	}
+
+	key := cacheKey(req)
+	if data, ok := rc.cache.Get(key); ok {
+		var cached cachedResponse
+		if err := json.Unmarshal(data, &cached); err == nil && clock.Now().Before(cached.ExpiresAt) {
+			return &http.Response{
+				Status:     fmt.Sprintf("%d %s", cached.StatusCode, http.StatusText(cached.StatusCode)),
+				StatusCode: cached.StatusCode,
+				Header:     cached.Header.Clone(),
+				Body:       io.NopCloser(bytes.NewReader(cached.Body)),
+				Request:    req,
+			}, nil
+		}
+	}
+
+	resp, err := next(req)
+	if err != nil || resp.StatusCode != http.StatusOK || noStore(resp.Header) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := json.Marshal(cachedResponse{
+		ExpiresAt:  clock.Now().Add(rc.ttl),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+	})
+	if err == nil {
+		rc.cache.Set(key, data)
+	}
+	return resp, nil
+}
+
+// noStore reports whether the response headers forbid caching
+func noStore(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
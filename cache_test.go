@@ -0,0 +1,144 @@
+package payjpv2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	customerBody := `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`
+	newClient := func(t *testing.T, apiKey string, cache Cache, ttl time.Duration, header http.Header, calls *int, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses(apiKey, append([]ClientOption{
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				*calls++
+				resp := jsonResponse(200, customerBody)
+				for k, v := range header {
+					resp.Header[k] = v
+				}
+				return resp, nil
+			})}),
+			WithCache(cache, ttl),
+		}, opts...)...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("serves a second identical GET from the cache", func(t *testing.T) {
+		calls := 0
+		client := newClient(t, "sk_test_example", NewMemoryCache(), time.Minute, nil, &calls)
+
+		for i := 0; i < 2; i++ {
+			resp, err := client.GetCustomerWithResponse(context.Background(), "cus_1")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.Result == nil || resp.Result.Id != "cus_1" {
+				t.Fatalf("Result incorrect on call %d: %+v", i+1, resp.Result)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("Round trip count incorrect. Got: %d, Expected: 1", calls)
+		}
+	})
+
+	t.Run("keys entries by API key", func(t *testing.T) {
+		calls := 0
+		cache := NewMemoryCache()
+		_, _ = newClient(t, "sk_test_a", cache, time.Minute, nil, &calls).GetCustomerWithResponse(context.Background(), "cus_1")
+		_, _ = newClient(t, "sk_test_b", cache, time.Minute, nil, &calls).GetCustomerWithResponse(context.Background(), "cus_1")
+		if calls != 2 {
+			t.Errorf("Round trip count incorrect. Got: %d, Expected: 2", calls)
+		}
+	})
+
+	t.Run("expires entries after the TTL on the client clock", func(t *testing.T) {
+		calls := 0
+		clock := newFakeClock()
+		client := newClient(t, "sk_test_example", NewMemoryCache(), time.Minute, nil, &calls, WithClock(clock))
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		clock.advance(59 * time.Second)
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if calls != 1 {
+			t.Errorf("Round trip count before expiry incorrect. Got: %d, Expected: 1", calls)
+		}
+		clock.advance(time.Second)
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if calls != 2 {
+			t.Errorf("Round trip count after expiry incorrect. Got: %d, Expected: 2", calls)
+		}
+	})
+
+	t.Run("rejects a non-positive TTL", func(t *testing.T) {
+		for _, ttl := range []time.Duration{0, -time.Second} {
+			if _, err := NewPayjpClientWithResponses("sk_test_example", WithCache(NewMemoryCache(), ttl)); err == nil {
+				t.Errorf("Expected an error for ttl %v", ttl)
+			}
+		}
+	})
+
+	t.Run("respects Cache-Control no-store", func(t *testing.T) {
+		calls := 0
+		client := newClient(t, "sk_test_example", NewMemoryCache(), time.Minute, http.Header{"Cache-Control": []string{"private, no-store"}}, &calls)
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if calls != 2 {
+			t.Errorf("Round trip count incorrect. Got: %d, Expected: 2", calls)
+		}
+	})
+
+	t.Run("does not cache other methods", func(t *testing.T) {
+		calls := 0
+		client := newClient(t, "sk_test_example", NewMemoryCache(), time.Minute, nil, &calls)
+		_, _ = client.DeleteCustomerWithResponse(context.Background(), "cus_1")
+		_, _ = client.DeleteCustomerWithResponse(context.Background(), "cus_1")
+		if calls != 2 {
+			t.Errorf("Round trip count incorrect. Got: %d, Expected: 2", calls)
+		}
+	})
+}
+
+func TestMemoryCache(t *testing.T) {
+	t.Run("evicts the least recently used entry when full", func(t *testing.T) {
+		cache := NewMemoryCacheWithLimit(2)
+		cache.Set("a", []byte("1"))
+		cache.Set("b", []byte("2"))
+		if _, ok := cache.Get("a"); !ok {
+			t.Fatal("Expected entry a")
+		}
+		cache.Set("c", []byte("3"))
+
+		if _, ok := cache.Get("b"); ok {
+			t.Error("Expected the least recently used entry b to be evicted")
+		}
+		for key, expected := range map[string]string{"a": "1", "c": "3"} {
+			if value, ok := cache.Get(key); !ok || string(value) != expected {
+				t.Errorf("Entry %s incorrect. Got: %q, %v, Expected: %q, true", key, value, ok, expected)
+			}
+		}
+	})
+
+	t.Run("stays bounded with many distinct keys", func(t *testing.T) {
+		cache := NewMemoryCache().(*memoryCache)
+		for i := 0; i < 3*defaultMemoryCacheEntries; i++ {
+			cache.Set(fmt.Sprintf("/v2/customers/cus_%d", i), []byte("{}"))
+		}
+		if len(cache.entries) != defaultMemoryCacheEntries || cache.order.Len() != defaultMemoryCacheEntries {
+			t.Errorf("Entry count incorrect. Got: %d, Expected: %d", len(cache.entries), defaultMemoryCacheEntries)
+		}
+	})
+
+	t.Run("overwrites an existing key without growing", func(t *testing.T) {
+		cache := NewMemoryCacheWithLimit(0)
+		cache.Set("a", []byte("1"))
+		cache.Set("a", []byte("2"))
+		if value, ok := cache.Get("a"); !ok || string(value) != "2" {
+			t.Errorf("Entry incorrect. Got: %q, %v, Expected: \"2\", true", value, ok)
+		}
+	})
+}
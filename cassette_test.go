@@ -0,0 +1,78 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestWithCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "create_customer.json")
+	email := openapi_types.Email("test@example.com")
+	body := CreateCustomerJSONRequestBody{Email: &email}
+
+	// Record the interaction against a stub "server"
+	calls := 0
+	server := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(200, `{"id":"cus_recorded","email":"test@example.com","livemode":false,"metadata":{}}`), nil
+	})
+	recorder, err := NewPayjpClientWithResponses("sk_test_secret", WithHTTPClient(&http.Client{Transport: server}), WithCassette(path, RecordModeAuto))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := Extract(recorder.CreateCustomerWithResponse(context.Background(), body, WithIdempotencyKey("key-1")))
+	if err != nil {
+		t.Fatalf("Record request failed: %v", err)
+	}
+	if resp.Result.Id != "cus_recorded" || calls != 1 {
+		t.Fatalf("Unexpected recorded result %q after %d calls", resp.Result.Id, calls)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cassette not written: %v", err)
+	}
+	if strings.Contains(string(data), "sk_test_secret") {
+		t.Error("Cassette must not contain the API key")
+	}
+
+	// Replay without touching the network
+	offline := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Error("Replay must not send requests")
+		return nil, errors.New("offline")
+	})
+	player, err := NewPayjpClientWithResponses("sk_test_secret", WithHTTPClient(&http.Client{Transport: offline}), WithCassette(path, RecordModeAuto))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err = Extract(player.CreateCustomerWithResponse(context.Background(), body, WithIdempotencyKey("key-1")))
+	if err != nil {
+		t.Fatalf("Replay request failed: %v", err)
+	}
+	if resp.Result.Id != "cus_recorded" {
+		t.Errorf("Replayed result incorrect. Got: %s, Expected: cus_recorded", resp.Result.Id)
+	}
+
+	t.Run("unmatched idempotency key misses", func(t *testing.T) {
+		_, err := player.CreateCustomerWithResponse(context.Background(), body, WithIdempotencyKey("key-2"))
+		if !errors.Is(err, ErrCassetteMiss) {
+			t.Errorf("Expected ErrCassetteMiss, got: %v", err)
+		}
+	})
+
+	t.Run("replay mode requires the cassette file", func(t *testing.T) {
+		_, err := NewPayjpClientWithResponses("sk_test_secret", WithCassette(filepath.Join(t.TempDir(), "missing.json"), RecordModeReplay))
+		if err == nil {
+			t.Error("Expected error for missing cassette in replay mode, got nil")
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package payjpv2
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxCheckoutLineItems is the maximum number of line items of a payment mode checkout session
+const maxCheckoutLineItems = 100
+
+// CheckoutOption sets fields of a request built by NewCheckoutSessionRequest.
+type CheckoutOption func(*CheckoutSessionCreateRequest) error
+
+// NewCheckoutSessionRequest builds a CheckoutSessionCreateRequest from opts, applied in order,
+// and checks the combinations the API requires before it is sent:
+//
+//   - a success URL is always required
+//   - payment mode, the default, needs 1 to 100 line items
+//   - setup mode takes no line items
+//
+// Example usage:
+//
+//	req, err := payjpv2.NewCheckoutSessionRequest(
+//	    payjpv2.WithLineItem("price_xxx", 1),
+//	    payjpv2.WithSuccessURL("https://example.com/checkout/success"),
+//	    payjpv2.WithCancelURL("https://example.com/cart"),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	resp, err := payjpv2.Extract(client.CreateCheckoutSessionWithResponse(ctx, req))
+func NewCheckoutSessionRequest(opts ...CheckoutOption) (CheckoutSessionCreateRequest, error) {
+	req := CheckoutSessionCreateRequest{Mode: CheckoutSessionModePayment}
+	for _, opt := range opts {
+		if err := opt(&req); err != nil {
+			return CheckoutSessionCreateRequest{}, err
+		}
+	}
+	if err := validateCheckoutSessionRequest(&req); err != nil {
+		return CheckoutSessionCreateRequest{}, err
+	}
+	return req, nil
+}
+
+// validateCheckoutSessionRequest checks the fields NewCheckoutSessionRequest requires
+func validateCheckoutSessionRequest(req *CheckoutSessionCreateRequest) error {
+	if req.SuccessUrl == nil {
+		return errors.New("checkout session requires a success URL")
+	}
+	var lineItems int
+	if req.LineItems != nil {
+		lineItems = len(*req.LineItems)
+	}
+	switch req.Mode {
+	case CheckoutSessionModePayment:
+		if lineItems == 0 {
+			return errors.New("payment mode checkout session requires at least one line item")
+		}
+		if lineItems > maxCheckoutLineItems {
+			return fmt.Errorf("payment mode checkout session accepts at most %d line items, got %d", maxCheckoutLineItems, lineItems)
+		}
+	case CheckoutSessionModeSetup:
+		if lineItems > 0 {
+			return errors.New("setup mode checkout session cannot have line items")
+		}
+	default:
+		return fmt.Errorf("unknown checkout session mode %q", req.Mode)
+	}
+	return nil
+}
+
+// WithCheckoutMode returns a CheckoutOption that sets the mode of the session. The default is
+// CheckoutSessionModePayment.
+func WithCheckoutMode(mode CheckoutSessionMode) CheckoutOption {
+	return func(req *CheckoutSessionCreateRequest) error {
+		req.Mode = mode
+		return nil
+	}
+}
+
+// WithLineItem returns a CheckoutOption that adds quantity units of the price to the session.
+func WithLineItem(priceID string, quantity int) CheckoutOption {
+	return func(req *CheckoutSessionCreateRequest) error {
+		if priceID == "" {
+			return errors.New("line item price ID cannot be empty")
+		}
+		if quantity < 1 {
+			return fmt.Errorf("line item quantity must be positive, got %d", quantity)
+		}
+		var items []LineItemRequest
+		if req.LineItems != nil {
+			items = *req.LineItems
+		}
+		items = append(items, LineItemRequest{PriceId: priceID, Quantity: quantity})
+		req.LineItems = &items
+		return nil
+	}
+}
+
+// WithSuccessURL returns a CheckoutOption that sets the URL the customer is redirected to after
+// completing the session. rawURL must be an absolute URL.
+func WithSuccessURL(rawURL string) CheckoutOption {
+	return func(req *CheckoutSessionCreateRequest) error {
+		if err := validateRedirectURL("success URL", rawURL); err != nil {
+			return err
+		}
+		req.SuccessUrl = &rawURL
+		return nil
+	}
+}
+
+// WithCancelURL returns a CheckoutOption that sets the URL the customer is redirected to when
+// leaving the session without completing it. rawURL must be an absolute URL.
+func WithCancelURL(rawURL string) CheckoutOption {
+	return func(req *CheckoutSessionCreateRequest) error {
+		if err := validateRedirectURL("cancel URL", rawURL); err != nil {
+			return err
+		}
+		req.CancelUrl = &rawURL
+		return nil
+	}
+}
+
+// HostedURL returns the URL of the hosted payment page to redirect the customer to. It returns
+// false if the session has no URL or is no longer open, i.e. already complete or expired.
+// The API calls the session response CheckoutSessionDetailsResponse.
+//
+// Example usage:
+//
+//	session, err := payjpv2.Extract(client.CreateCheckoutSessionWithResponse(ctx, req))
+//	if err != nil {
+//	    return err
+//	}
+//	if hostedURL, ok := session.Result.HostedURL(); ok {
+//	    http.Redirect(w, r, hostedURL, http.StatusSeeOther)
+//	}
+func (s *CheckoutSessionDetailsResponse) HostedURL() (string, bool) {
+	if s == nil || s.Url == "" || s.Status != CheckoutSessionStatusOpen {
+		return "", false
+	}
+	return s.Url, true
+}
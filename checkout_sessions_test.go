@@ -0,0 +1,102 @@
+package payjpv2
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewCheckoutSessionRequest(t *testing.T) {
+	t.Run("builds a valid payment session", func(t *testing.T) {
+		req, err := NewCheckoutSessionRequest(
+			WithLineItem("price_1", 2),
+			WithLineItem("price_2", 1),
+			WithSuccessURL("https://example.com/success"),
+			WithCancelURL("https://example.com/cart"),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.Mode != CheckoutSessionModePayment {
+			t.Errorf("Mode incorrect. Got: %s, Expected: payment", req.Mode)
+		}
+		if req.LineItems == nil || len(*req.LineItems) != 2 {
+			t.Fatalf("Line items incorrect. Got: %+v", req.LineItems)
+		}
+		if item := (*req.LineItems)[0]; item.PriceId != "price_1" || item.Quantity != 2 {
+			t.Errorf("First line item incorrect. Got: %+v", item)
+		}
+		if req.SuccessUrl == nil || *req.SuccessUrl != "https://example.com/success" {
+			t.Errorf("Success URL incorrect. Got: %v", req.SuccessUrl)
+		}
+		if req.CancelUrl == nil || *req.CancelUrl != "https://example.com/cart" {
+			t.Errorf("Cancel URL incorrect. Got: %v", req.CancelUrl)
+		}
+	})
+
+	t.Run("builds a setup session without line items", func(t *testing.T) {
+		req, err := NewCheckoutSessionRequest(
+			WithCheckoutMode(CheckoutSessionModeSetup),
+			WithSuccessURL("https://example.com/success"),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.Mode != CheckoutSessionModeSetup {
+			t.Errorf("Mode incorrect. Got: %s, Expected: setup", req.Mode)
+		}
+	})
+
+	invalid := []struct {
+		name     string
+		opts     []CheckoutOption
+		expected string
+	}{
+		{"missing success URL", []CheckoutOption{WithLineItem("price_1", 1)}, "success URL"},
+		{"relative success URL", []CheckoutOption{WithLineItem("price_1", 1), WithSuccessURL("/success")}, "success URL"},
+		{"payment without line items", []CheckoutOption{WithSuccessURL("https://example.com/success")}, "line item"},
+		{"setup with line items", []CheckoutOption{WithCheckoutMode(CheckoutSessionModeSetup), WithLineItem("price_1", 1), WithSuccessURL("https://example.com/success")}, "line items"},
+		{"zero quantity", []CheckoutOption{WithLineItem("price_1", 0), WithSuccessURL("https://example.com/success")}, "quantity"},
+		{"unknown mode", []CheckoutOption{WithCheckoutMode("subscription"), WithSuccessURL("https://example.com/success")}, "mode"},
+	}
+	for _, tt := range invalid {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			_, err := NewCheckoutSessionRequest(tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error mentioning %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckoutSessionHostedURL(t *testing.T) {
+	decode := func(t *testing.T, status string) *CheckoutSessionDetailsResponse {
+		t.Helper()
+		body := `{"id":"cs_1","object":"checkout.session","livemode":false,"mode":"payment","status":"` + status + `","ui_mode":"hosted","url":"https://checkout.pay.jp/c/pay/cs_1","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`
+		var session CheckoutSessionDetailsResponse
+		if err := json.Unmarshal([]byte(body), &session); err != nil {
+			t.Fatalf("Failed to decode session: %v", err)
+		}
+		return &session
+	}
+
+	t.Run("returns the URL of an open session", func(t *testing.T) {
+		hostedURL, ok := decode(t, "open").HostedURL()
+		if !ok || hostedURL != "https://checkout.pay.jp/c/pay/cs_1" {
+			t.Errorf("HostedURL incorrect. Got: %q, %v, Expected: https://checkout.pay.jp/c/pay/cs_1, true", hostedURL, ok)
+		}
+	})
+
+	t.Run("returns false for a completed session", func(t *testing.T) {
+		if hostedURL, ok := decode(t, "complete").HostedURL(); ok {
+			t.Errorf("Expected no URL, got: %q", hostedURL)
+		}
+	})
+
+	t.Run("returns false for nil", func(t *testing.T) {
+		var session *CheckoutSessionDetailsResponse
+		if _, ok := session.HostedURL(); ok {
+			t.Error("Expected no URL for nil")
+		}
+	})
+}
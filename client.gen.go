@@ -6122,7 +6122,7 @@ func NewClient(server string, opts ...ClientOption) (*Client, error) {
 // automatically created using http.Client. This is useful for tests.
 func WithHTTPClient(doer HttpRequestDoer) ClientOption {
 	return func(c *Client) error {
-		c.Client = doer
+		setHTTPClient(c, doer)
 		return nil
 	}
 }
@@ -0,0 +1,68 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Clock is the source of time for the SDK's time-dependent behavior, such as retry backoff and
+// polling. Replace it with WithClock to test that behavior without waiting.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After returns a channel that receives the current time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the Clock backed by the time package
+type systemClock struct{}
+
+// Now implements Clock.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After implements Clock.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithClock returns a ClientOption that makes the client use clock instead of the system clock.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) error {
+		if clock == nil {
+			return errors.New("clock cannot be nil")
+		}
+		sdkDoerFor(c).clock = clock
+		return nil
+	}
+}
+
+// clockOf returns the clock of c, or the system clock if none was set
+func (c *ClientWithResponses) clockOf() Clock {
+	if client, ok := c.ClientInterface.(*Client); ok {
+		if d, ok := client.Client.(*sdkDoer); ok {
+			return d.clockOrDefault()
+		}
+	}
+	return systemClock{}
+}
+
+// clockOrDefault returns the clock of d, or the system clock if none was set
+func (d *sdkDoer) clockOrDefault() Clock {
+	if d.clock == nil {
+		return systemClock{}
+	}
+	return d.clock
+}
+
+// sleep waits for d on clock or until ctx is done
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
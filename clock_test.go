@@ -0,0 +1,92 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose timers fire immediately, advancing the clock by their duration
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// advance moves the clock forward by d, e.g. to simulate a slow request
+func (f *fakeClock) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.sleeps = append(f.sleeps, d)
+	ch := make(chan time.Time, 1)
+	ch <- f.now
+	return ch
+}
+
+func TestWithClock(t *testing.T) {
+	t.Run("sets the client clock", func(t *testing.T) {
+		clock := newFakeClock()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithClock(clock))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if client.clockOf() != Clock(clock) {
+			t.Error("Expected the client to use the fake clock")
+		}
+	})
+
+	t.Run("defaults to the system clock", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_example")
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if _, ok := client.clockOf().(systemClock); !ok {
+			t.Errorf("Expected the system clock, got: %T", client.clockOf())
+		}
+	})
+
+	t.Run("rejects a nil clock", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_example", WithClock(nil)); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestSleep(t *testing.T) {
+	t.Run("waits on the clock", func(t *testing.T) {
+		clock := newFakeClock()
+		if err := sleep(context.Background(), clock, time.Minute); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(clock.sleeps) != 1 || clock.sleeps[0] != time.Minute {
+			t.Errorf("Sleeps incorrect. Got: %v, Expected: [1m0s]", clock.sleeps)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := sleep(ctx, systemClock{}, time.Hour); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got: %v", err)
+		}
+	})
+}
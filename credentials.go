@@ -0,0 +1,84 @@
+package payjpv2
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// KeyType is the mode of a PAY.JP API key.
+type KeyType string
+
+const (
+	// KeyTypeUnknown is returned when the key type could not be determined
+	KeyTypeUnknown KeyType = ""
+	// KeyTypeTest is a test mode key (sk_test_...)
+	KeyTypeTest KeyType = "test"
+	// KeyTypeLive is a live mode key (sk_live_...)
+	KeyTypeLive KeyType = "live"
+)
+
+// keyTypeOf classifies an API key by its prefix
+func keyTypeOf(apiKey string) KeyType {
+	switch {
+	case strings.HasPrefix(apiKey, "sk_test_"):
+		return KeyTypeTest
+	case strings.HasPrefix(apiKey, "sk_live_"):
+		return KeyTypeLive
+	default:
+		return KeyTypeUnknown
+	}
+}
+
+// apiKeyPrefixes are the key prefixes kept by MaskAPIKey, longest first
+var apiKeyPrefixes = []string{"sk_test_", "sk_live_", "pk_test_", "pk_live_", "sk_", "pk_"}
+
+// MaskAPIKey returns key with its prefix and last 4 characters kept and the rest masked, for
+// displaying configuration safely. It returns "***" for keys that are not sk_ or pk_ keys or are
+// too short to keep the last 4 characters while masking at least as many.
+//
+// Example usage:
+//
+//	log.Printf("using key %s", payjpv2.MaskAPIKey(apiKey)) // using key sk_test_****abcd
+func MaskAPIKey(key string) string {
+	for _, prefix := range apiKeyPrefixes {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		secret := key[len(prefix):]
+		if len(secret) < 8 {
+			return "***"
+		}
+		return prefix + "****" + secret[len(secret)-4:]
+	}
+	return "***"
+}
+
+// VerifyCredentials checks that the client's API key is accepted by making a minimal read-only
+// call, and returns whether it is a test or live key. A rejected key is returned as an *APIError
+// with status 401. Use it at startup to fail fast on misconfiguration.
+//
+// Example usage:
+//
+//	keyType, err := client.VerifyCredentials(ctx)
+//	if err != nil {
+//	    log.Fatalf("invalid PAY.JP credentials: %v", err)
+//	}
+//	if keyType != payjpv2.KeyTypeLive {
+//	    log.Print("running with a test key")
+//	}
+func (c *ClientWithResponses) VerifyCredentials(ctx context.Context, reqEditors ...RequestEditorFn) (KeyType, error) {
+	var authorization string
+	captureAuthorization := func(ctx context.Context, req *http.Request) error {
+		authorization = req.Header.Get("Authorization")
+		return nil
+	}
+
+	limit := 1
+	params := &GetAllCustomersParams{Limit: &limit}
+	if _, err := Extract(c.GetAllCustomersWithResponse(ctx, params, append(slices.Clone(reqEditors), captureAuthorization)...)); err != nil {
+		return KeyTypeUnknown, err
+	}
+	return keyTypeOf(strings.TrimPrefix(authorization, "Bearer ")), nil
+}
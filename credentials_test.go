@@ -0,0 +1,96 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyCredentials(t *testing.T) {
+	newClient := func(t *testing.T, apiKey string, status int, body string) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses(apiKey, WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodGet {
+					t.Errorf("Method incorrect. Got: %s, Expected: GET", req.Method)
+				}
+				return jsonResponse(status, body), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+	emptyList := `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`
+
+	t.Run("classifies a valid test key", func(t *testing.T) {
+		keyType, err := newClient(t, "sk_test_example", 200, emptyList).VerifyCredentials(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if keyType != KeyTypeTest {
+			t.Errorf("Key type incorrect. Got: %q, Expected: %q", keyType, KeyTypeTest)
+		}
+	})
+
+	t.Run("classifies a valid live key", func(t *testing.T) {
+		keyType, err := newClient(t, "sk_live_example", 200, emptyList).VerifyCredentials(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if keyType != KeyTypeLive {
+			t.Errorf("Key type incorrect. Got: %q, Expected: %q", keyType, KeyTypeLive)
+		}
+	})
+
+	t.Run("returns APIError for an invalid key", func(t *testing.T) {
+		client := newClient(t, "sk_test_revoked", 401, `{"status":401,"title":"Unauthorized","type":"about:blank"}`)
+		keyType, err := client.VerifyCredentials(context.Background())
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected 401 APIError, got: %v", err)
+		}
+		if keyType != KeyTypeUnknown {
+			t.Errorf("Key type incorrect. Got: %q, Expected: unknown", keyType)
+		}
+	})
+
+	t.Run("does not write into the caller's editors", func(t *testing.T) {
+		editors := make([]RequestEditorFn, 1, 2)
+		editors[0] = func(ctx context.Context, req *http.Request) error { return nil }
+		if _, err := newClient(t, "sk_test_example", 200, emptyList).VerifyCredentials(context.Background(), editors...); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if spare := editors[:2][1]; spare != nil {
+			t.Error("Expected the spare capacity of the editors to be left untouched")
+		}
+	})
+}
+
+func TestMaskAPIKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{"secret test key", "sk_test_c62fade9d045b54cd76d7036", "sk_test_****7036"},
+		{"secret live key", "sk_live_0123456789abcdef", "sk_live_****cdef"},
+		{"public test key", "pk_test_0e4fc2bdb8ec20e6ec8d8e4b", "pk_test_****8e4b"},
+		{"key without mode", "sk_0123456789", "sk_****6789"},
+		{"too short", "sk_test_1234567", "***"},
+		{"prefix only", "sk_test_", "***"},
+		{"empty", "", "***"},
+		{"unknown prefix", "rk_test_0123456789abcdef", "***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskAPIKey(tt.key); got != tt.expected {
+				t.Errorf("Masked key incorrect. Got: %s, Expected: %s", got, tt.expected)
+			}
+		})
+	}
+}
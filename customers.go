@@ -0,0 +1,383 @@
+package payjpv2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Metadata is a set of metadata changes. A key with an empty value is deleted.
+type Metadata map[string]string
+
+// isTimeout reports whether err is a transport or per-call timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// CreateCustomerSafe creates a customer with the given idempotency key. If the request times out
+// before a response is received, it is re-issued once with the identical key, so the customer is
+// created at most once and the call learns the true outcome.
+//
+// replayed is true when the timed-out attempt had already created the customer and the retry
+// returned that customer. Before retrying, the newest page of customers is listed; the customer
+// was replayed if the retry returned one of them. Customers are compared by id, so the result
+// does not depend on the local clock. The retry is not made when ctx itself is done or the
+// listing fails.
+//
+// Example usage:
+//
+//	customer, replayed, err := client.CreateCustomerSafe(ctx, req, idempotencyKey)
+//	if err != nil {
+//	    return err
+//	}
+//	if replayed {
+//	    log.Printf("customer %s was created by the timed-out request", customer.Id)
+//	}
+func (c *ClientWithResponses) CreateCustomerSafe(ctx context.Context, req CustomerCreateRequest, idempotencyKey string, reqEditors ...RequestEditorFn) (customer *CustomerResponse, replayed bool, err error) {
+	if idempotencyKey == "" {
+		return nil, false, errors.New("idempotency key cannot be empty")
+	}
+	createEditors := append(slices.Clone(reqEditors), WithIdempotencyKey(idempotencyKey))
+
+	resp, err := Extract(c.CreateCustomerWithResponse(ctx, req, createEditors...))
+	if err != nil {
+		if !isTimeout(err) || ctx.Err() != nil {
+			return nil, false, err
+		}
+		existing, err := c.newestCustomerIDs(ctx, reqEditors...)
+		if err != nil {
+			return nil, false, err
+		}
+		resp, err = Extract(c.CreateCustomerWithResponse(ctx, req, createEditors...))
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Result != nil {
+			replayed = existing[resp.Result.Id]
+		}
+	}
+	if resp.Result == nil {
+		return nil, false, errors.New("create customer response has no result")
+	}
+	return resp.Result, replayed, nil
+}
+
+// newestCustomerIDs returns the ids of the newest page of customers
+func (c *ClientWithResponses) newestCustomerIDs(ctx context.Context, reqEditors ...RequestEditorFn) (map[string]bool, error) {
+	limit := listPageLimit
+	resp, err := Extract(c.GetAllCustomersWithResponse(ctx, &GetAllCustomersParams{Limit: &limit}, reqEditors...))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, errors.New("list customers response has no result")
+	}
+	ids := make(map[string]bool, len(resp.Result.Data))
+	for _, customer := range resp.Result.Data {
+		ids[customer.Id] = true
+	}
+	return ids, nil
+}
+
+// UpdateCustomerMetadata updates only the metadata of a customer. The request body contains just
+// the metadata field, so the customer's other fields are left untouched. Keys in md are added or
+// overwritten, and keys with an empty value are deleted; keys not in md are kept.
+//
+// Example usage:
+//
+//	customer, err := client.UpdateCustomerMetadata(ctx, "cus_xxx", payjpv2.Metadata{
+//	    "plan":   "premium",
+//	    "coupon": "", // deletes the coupon key
+//	})
+func (c *ClientWithResponses) UpdateCustomerMetadata(ctx context.Context, id string, md Metadata, reqEditors ...RequestEditorFn) (*CustomerResponse, error) {
+	if len(md) == 0 {
+		return nil, errors.New("metadata cannot be empty")
+	}
+	body, err := json.Marshal(struct {
+		Metadata Metadata `json:"metadata"`
+	}{md})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := Extract(c.UpdateCustomerWithBodyWithResponse(ctx, id, "application/json", bytes.NewReader(body), reqEditors...))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, errors.New("update customer response has no result")
+	}
+	return resp.Result, nil
+}
+
+// customers returns an iterator over the customers listed with params, fetching further pages
+// lazily. A nil params or Limit uses the maximum page size.
+func (c *ClientWithResponses) customers(ctx context.Context, params *GetAllCustomersParams, reqEditors ...RequestEditorFn) iter.Seq2[*CustomerResponse, error] {
+	base := GetAllCustomersParams{}
+	if params != nil {
+		base = *params
+	}
+	if base.Limit == nil {
+		limit := listPageLimit
+		base.Limit = &limit
+	}
+	return paginate(ctx, c.maxPagesOf(), func(startingAfter *string) ([]CustomerResponse, bool, error) {
+		page := base
+		if startingAfter != nil {
+			page.StartingAfter = startingAfter
+		}
+		resp, err := Extract(c.GetAllCustomersWithResponse(ctx, &page, reqEditors...))
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Result == nil {
+			return nil, false, errors.New("list customers response has no result")
+		}
+		return resp.Result.Data, resp.Result.HasMore, nil
+	}, func(c *CustomerResponse) string { return c.Id })
+}
+
+// CustomersService groups the customer helpers that are not methods of ClientWithResponses.
+type CustomersService struct {
+	client *ClientWithResponses
+}
+
+// Customers returns the customer helpers of client.
+func Customers(client *ClientWithResponses) *CustomersService {
+	return &CustomersService{client: client}
+}
+
+// Iterate returns an Iterator over the customers listed with params, fetching each page when the
+// previous one has been consumed. A nil params or Limit uses the maximum page size.
+//
+// Example usage:
+//
+//	it := payjpv2.Customers(client).Iterate(ctx, nil)
+//	for it.Next(ctx) {
+//	    fmt.Println(it.Item().Id)
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+func (s *CustomersService) Iterate(ctx context.Context, params *GetAllCustomersParams, reqEditors ...RequestEditorFn) *Iterator[CustomerResponse] {
+	base := GetAllCustomersParams{}
+	if params != nil {
+		base = *params
+	}
+	if base.Limit == nil {
+		limit := listPageLimit
+		base.Limit = &limit
+	}
+	return newIterator(ctx, *base.Limit, s.client.maxPagesOf(), func(ctx context.Context, startingAfter *string) ([]CustomerResponse, bool, error) {
+		page := base
+		if startingAfter != nil {
+			page.StartingAfter = startingAfter
+		}
+		resp, err := Extract(s.client.GetAllCustomersWithResponse(ctx, &page, reqEditors...))
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Result == nil {
+			return nil, false, errors.New("list customers response has no result")
+		}
+		return resp.Result.Data, resp.Result.HasMore, nil
+	}, func(c *CustomerResponse) string { return c.Id })
+}
+
+// FindCustomer returns the first customer listed with params for which match returns true, or nil
+// if there is none. Pages are fetched only until a match is found.
+//
+// Example usage:
+//
+//	customer, err := client.FindCustomer(ctx, nil, func(c *payjpv2.CustomerResponse) bool {
+//	    return c.Email != nil && string(*c.Email) == "taro@example.com"
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	if customer == nil {
+//	    // not found
+//	}
+func (c *ClientWithResponses) FindCustomer(ctx context.Context, params *GetAllCustomersParams, match func(*CustomerResponse) bool, reqEditors ...RequestEditorFn) (*CustomerResponse, error) {
+	for customer, err := range c.customers(ctx, params, reqEditors...) {
+		if err != nil {
+			return nil, err
+		}
+		if match(customer) {
+			return customer, nil
+		}
+	}
+	return nil, nil
+}
+
+// AllCustomersSlice returns every customer listed with params, fetching all pages. A nil params
+// or Limit uses the maximum page size.
+//
+// If a page fails, the customers of the pages fetched before it are returned together with the
+// error, so a caller can keep or checkpoint the partial result. Callers that need all or nothing
+// must check the error before using the slice.
+//
+// Example usage:
+//
+//	customers, err := client.AllCustomersSlice(ctx, nil)
+//	if err != nil {
+//	    log.Printf("listed %d customers before failing: %v", len(customers), err)
+//	    return err
+//	}
+func (c *ClientWithResponses) AllCustomersSlice(ctx context.Context, params *GetAllCustomersParams, reqEditors ...RequestEditorFn) ([]CustomerResponse, error) {
+	var customers []CustomerResponse
+	for customer, err := range c.customers(ctx, params, reqEditors...) {
+		if err != nil {
+			return customers, err
+		}
+		customers = append(customers, *customer)
+	}
+	return customers, nil
+}
+
+// CustomersModifiedSince iterates over the customers whose UpdatedAt is at or after t, for
+// incremental syncs. UpdatedAt changes on creation and on every update of the customer.
+//
+// The customers endpoint cannot filter by update time, so every customer is listed with the
+// largest page size and filtered locally. An error ends the iteration after being yielded.
+//
+// Example usage:
+//
+//	for customer, err := range client.CustomersModifiedSince(ctx, lastSync) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(customer.Id)
+//	}
+func (c *ClientWithResponses) CustomersModifiedSince(ctx context.Context, t time.Time, reqEditors ...RequestEditorFn) iter.Seq2[*CustomerResponse, error] {
+	return func(yield func(*CustomerResponse, error) bool) {
+		for customer, err := range c.customers(ctx, nil, reqEditors...) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if customer.UpdatedAt.Before(t) {
+				continue
+			}
+			if !yield(customer, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ErrAmbiguousCustomer is returned by UpsertCustomerByEmail when several customers have the email.
+var ErrAmbiguousCustomer = errors.New("multiple customers have the email")
+
+// UpsertCustomerByEmail updates the customer with the given email with req, or creates a customer
+// with that email and the fields of req (DefaultPaymentMethodId becomes the created customer's
+// PaymentMethodId) if there is none. created reports which happened. Emails are compared
+// case-insensitively. If several customers have the email, nothing is changed and an error
+// wrapping ErrAmbiguousCustomer is returned.
+//
+// The customers endpoint cannot filter by email, so every customer is listed to find matches.
+// Only the fields of req that are set are sent on update, so a nil DefaultPaymentMethodId keeps
+// the customer's default payment method.
+//
+// Example usage:
+//
+//	customer, created, err := client.UpsertCustomerByEmail(ctx, "taro@example.com", payjpv2.CustomerUpdateRequest{
+//	    Description: &description,
+//	})
+func (c *ClientWithResponses) UpsertCustomerByEmail(ctx context.Context, email string, req CustomerUpdateRequest, reqEditors ...RequestEditorFn) (customer *CustomerResponse, created bool, err error) {
+	if email == "" {
+		return nil, false, errors.New("email cannot be empty")
+	}
+
+	var matches []*CustomerResponse
+	for customer, err := range c.customers(ctx, nil, reqEditors...) {
+		if err != nil {
+			return nil, false, err
+		}
+		if customer.Email != nil && strings.EqualFold(string(*customer.Email), email) {
+			matches = append(matches, customer)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		createReq, err := customerCreateRequestFrom(email, req)
+		if err != nil {
+			return nil, false, err
+		}
+		resp, err := Extract(c.CreateCustomerWithResponse(ctx, createReq, reqEditors...))
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Result == nil {
+			return nil, false, errors.New("create customer response has no result")
+		}
+		return resp.Result, true, nil
+	case 1:
+		emailValue := openapi_types.Email(email)
+		req.Email = &emailValue
+		body, err := customerUpdateBody(req)
+		if err != nil {
+			return nil, false, err
+		}
+		resp, err := Extract(c.UpdateCustomerWithBodyWithResponse(ctx, matches[0].Id, "application/json", bytes.NewReader(body), reqEditors...))
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Result == nil {
+			return nil, false, errors.New("update customer response has no result")
+		}
+		return resp.Result, false, nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.Id
+		}
+		return nil, false, fmt.Errorf("%w: %s", ErrAmbiguousCustomer, strings.Join(ids, ", "))
+	}
+}
+
+// customerCreateRequestFrom builds the request creating a customer with email and the fields of req
+func customerCreateRequestFrom(email string, req CustomerUpdateRequest) (CustomerCreateRequest, error) {
+	emailValue := openapi_types.Email(email)
+	createReq := CustomerCreateRequest{
+		Description:     req.Description,
+		Email:           &emailValue,
+		PaymentMethodId: req.DefaultPaymentMethodId,
+	}
+	if req.Metadata != nil {
+		// The metadata value unions of both requests share the same JSON encoding
+		data, err := json.Marshal(req.Metadata)
+		if err != nil {
+			return CustomerCreateRequest{}, err
+		}
+		if err := json.Unmarshal(data, &createReq.Metadata); err != nil {
+			return CustomerCreateRequest{}, err
+		}
+	}
+	return createReq, nil
+}
+
+// customerUpdateBody encodes only the fields of req that are set. The generated request always
+// encodes default_payment_method_id, and a null there clears the customer's default.
+func customerUpdateBody(req CustomerUpdateRequest) ([]byte, error) {
+	return json.Marshal(struct {
+		DefaultPaymentMethodId *string                                                         `json:"default_payment_method_id,omitempty"`
+		Description            *string                                                         `json:"description,omitempty"`
+		Email                  *openapi_types.Email                                            `json:"email,omitempty"`
+		Metadata               *map[string]CustomerUpdateRequest_Metadata_AdditionalProperties `json:"metadata,omitempty"`
+	}{req.DefaultPaymentMethodId, req.Description, req.Email, req.Metadata})
+}
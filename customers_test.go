@@ -0,0 +1,564 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestCreateCustomerSafe(t *testing.T) {
+	customerJSON := func(createdAt time.Time) string {
+		return fmt.Sprintf(`{"id":"cus_1","object":"customer","livemode":false,"created_at":%q,"updated_at":%q,"metadata":{}}`,
+			createdAt.UTC().Format(time.RFC3339), createdAt.UTC().Format(time.RFC3339))
+	}
+	newClient := func(t *testing.T, rt roundTripFunc) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{Transport: rt}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+	// timeoutThenCreate times out the first create, then lists the customers and answers the retry
+	timeoutThenCreate := func(requests *[]string, listed string, createdAt time.Time) roundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			*requests = append(*requests, req.Method+" "+req.Header.Get("Idempotency-Key"))
+			switch {
+			case req.Method == http.MethodGet:
+				return jsonResponse(200, `{"object":"list","url":"/v2/customers","has_more":false,"data":[`+listed+`]}`), nil
+			case len(*requests) == 1:
+				return nil, timeoutError{}
+			default:
+				return jsonResponse(200, customerJSON(createdAt)), nil
+			}
+		}
+	}
+
+	t.Run("retries a timeout with the same key and detects the replay", func(t *testing.T) {
+		var requests []string
+		// created_at ahead of the local clock, as with clock skew, does not matter
+		createdAt := time.Now().Add(time.Hour)
+		client := newClient(t, timeoutThenCreate(&requests, customerJSON(createdAt), createdAt))
+
+		customer, replayed, err := client.CreateCustomerSafe(context.Background(), CustomerCreateRequest{}, "key_1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := strings.Join(requests, ", "); got != "POST key_1, GET , POST key_1" {
+			t.Errorf("Requests incorrect. Got: %s, Expected: POST key_1, GET , POST key_1", got)
+		}
+		if customer.Id != "cus_1" {
+			t.Errorf("Customer ID incorrect. Got: %s, Expected: cus_1", customer.Id)
+		}
+		if !replayed {
+			t.Error("Expected replayed to be true")
+		}
+	})
+
+	t.Run("reports creation by the retry", func(t *testing.T) {
+		var requests []string
+		// created_at behind the local clock, as with clock skew, does not matter
+		client := newClient(t, timeoutThenCreate(&requests, "", time.Now().Add(-time.Hour)))
+
+		_, replayed, err := client.CreateCustomerSafe(context.Background(), CustomerCreateRequest{}, "key_1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if replayed {
+			t.Error("Expected replayed to be false")
+		}
+	})
+
+	t.Run("does not retry when the listing fails", func(t *testing.T) {
+		calls := 0
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			calls++
+			if req.Method == http.MethodGet {
+				return jsonResponse(500, `{"status":500,"title":"Internal Server Error","type":"about:blank"}`), nil
+			}
+			return nil, timeoutError{}
+		})
+
+		if _, _, err := client.CreateCustomerSafe(context.Background(), CustomerCreateRequest{}, "key_1"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+		if calls != 2 {
+			t.Errorf("Call count incorrect. Got: %d, Expected: 2", calls)
+		}
+	})
+
+	t.Run("does not retry on success or other errors", func(t *testing.T) {
+		calls := 0
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			calls++
+			return jsonResponse(200, customerJSON(time.Now())), nil
+		})
+		if _, replayed, err := client.CreateCustomerSafe(context.Background(), CustomerCreateRequest{}, "key_1"); err != nil || replayed {
+			t.Errorf("Unexpected result: replayed=%v err=%v", replayed, err)
+		}
+
+		failing := newClient(t, func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, errors.New("connection refused")
+		})
+		if _, _, err := failing.CreateCustomerSafe(context.Background(), CustomerCreateRequest{}, "key_1"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+		if calls != 2 {
+			t.Errorf("Call count incorrect. Got: %d, Expected: 2", calls)
+		}
+	})
+
+	t.Run("requires an idempotency key", func(t *testing.T) {
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			t.Fatal("Unexpected request")
+			return nil, nil
+		})
+		if _, _, err := client.CreateCustomerSafe(context.Background(), CustomerCreateRequest{}, ""); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestUpdateCustomerMetadata(t *testing.T) {
+	newClient := func(t *testing.T, gotBody *string) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method != http.MethodPost || req.URL.Path != "/v2/customers/cus_1" {
+					t.Errorf("Request incorrect. Got: %s %s, Expected: POST /v2/customers/cus_1", req.Method, req.URL.Path)
+				}
+				body, _ := io.ReadAll(req.Body)
+				*gotBody = string(body)
+				return jsonResponse(200, `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{"plan":"premium"}}`), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("sends only the metadata field", func(t *testing.T) {
+		var body string
+		customer, err := newClient(t, &body).UpdateCustomerMetadata(context.Background(), "cus_1", Metadata{"plan": "premium"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := `{"metadata":{"plan":"premium"}}`; body != expected {
+			t.Errorf("Body incorrect. Got: %s, Expected: %s", body, expected)
+		}
+		if customer.Id != "cus_1" {
+			t.Errorf("Id incorrect. Got: %s, Expected: cus_1", customer.Id)
+		}
+	})
+
+	t.Run("deletes keys with an empty value", func(t *testing.T) {
+		var body string
+		if _, err := newClient(t, &body).UpdateCustomerMetadata(context.Background(), "cus_1", Metadata{"coupon": ""}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := `{"metadata":{"coupon":""}}`; body != expected {
+			t.Errorf("Body incorrect. Got: %s, Expected: %s", body, expected)
+		}
+	})
+
+	t.Run("requires metadata", func(t *testing.T) {
+		var body string
+		if _, err := newClient(t, &body).UpdateCustomerMetadata(context.Background(), "cus_1", nil); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestFindCustomer(t *testing.T) {
+	customer := func(id, email string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"customer","email":%q,"livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`, id, email)
+	}
+	pages := map[string]string{
+		"":      `{"object":"list","url":"/v2/customers","has_more":true,"data":[` + customer("cus_1", "a@example.com") + "," + customer("cus_2", "b@example.com") + `]}`,
+		"cus_2": `{"object":"list","url":"/v2/customers","has_more":false,"data":[` + customer("cus_3", "c@example.com") + `]}`,
+	}
+	newClient := func(t *testing.T, queries *[]string) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				*queries = append(*queries, req.URL.RawQuery)
+				return jsonResponse(200, pages[req.URL.Query().Get("starting_after")]), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+	byEmail := func(email string) func(*CustomerResponse) bool {
+		return func(c *CustomerResponse) bool {
+			return c.Email != nil && string(*c.Email) == email
+		}
+	}
+
+	t.Run("stops once a match is found on page one", func(t *testing.T) {
+		var queries []string
+		found, err := newClient(t, &queries).FindCustomer(context.Background(), nil, byEmail("b@example.com"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if found == nil || found.Id != "cus_2" {
+			t.Errorf("Customer incorrect. Got: %+v, Expected: cus_2", found)
+		}
+		if len(queries) != 1 {
+			t.Errorf("Request count incorrect. Got: %d, Expected: 1", len(queries))
+		}
+	})
+
+	t.Run("pages until a match with the given params", func(t *testing.T) {
+		var queries []string
+		limit := 2
+		found, err := newClient(t, &queries).FindCustomer(context.Background(), &GetAllCustomersParams{Limit: &limit}, byEmail("c@example.com"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if found == nil || found.Id != "cus_3" {
+			t.Errorf("Customer incorrect. Got: %+v, Expected: cus_3", found)
+		}
+		expected := "limit=2 limit=2&starting_after=cus_2"
+		if got := strings.Join(queries, " "); got != expected {
+			t.Errorf("Queries incorrect. Got: %s, Expected: %s", got, expected)
+		}
+	})
+
+	t.Run("returns nil without a match", func(t *testing.T) {
+		var queries []string
+		found, err := newClient(t, &queries).FindCustomer(context.Background(), nil, byEmail("z@example.com"))
+		if err != nil || found != nil {
+			t.Errorf("Expected nil, nil. Got: %+v, %v", found, err)
+		}
+	})
+}
+
+func TestAllCustomersSlice(t *testing.T) {
+	customer := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`, id)
+	}
+	newClient := func(t *testing.T, secondPage *http.Response) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Query().Get("starting_after") == "cus_2" {
+					return secondPage, nil
+				}
+				return jsonResponse(200, `{"object":"list","url":"/v2/customers","has_more":true,"data":[`+customer("cus_1")+","+customer("cus_2")+`]}`), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("collects every page", func(t *testing.T) {
+		client := newClient(t, jsonResponse(200, `{"object":"list","url":"/v2/customers","has_more":false,"data":[`+customer("cus_3")+`]}`))
+		customers, err := client.AllCustomersSlice(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(customers) != 3 || customers[2].Id != "cus_3" {
+			t.Errorf("Customers incorrect. Got: %+v", customers)
+		}
+	})
+
+	t.Run("returns the first page with the error of the second", func(t *testing.T) {
+		client := newClient(t, jsonResponse(500, `{"status":500,"title":"Internal Server Error","type":"about:blank"}`))
+		customers, err := client.AllCustomersSlice(context.Background(), nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+			t.Errorf("Expected 500 APIError, got: %v", err)
+		}
+		if len(customers) != 2 || customers[0].Id != "cus_1" || customers[1].Id != "cus_2" {
+			t.Errorf("Partial customers incorrect. Got: %+v, Expected: cus_1, cus_2", customers)
+		}
+	})
+}
+
+func TestCustomersModifiedSince(t *testing.T) {
+	customer := func(id, updatedAt string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":%q,"metadata":{}}`, id, updatedAt)
+	}
+	pages := map[string]string{
+		"":      `{"object":"list","url":"/v2/customers","has_more":true,"data":[` + customer("cus_1", "2024-03-01T00:00:00Z") + "," + customer("cus_2", "2024-01-15T00:00:00Z") + `]}`,
+		"cus_2": `{"object":"list","url":"/v2/customers","has_more":false,"data":[` + customer("cus_3", "2024-02-01T00:00:00Z") + `]}`,
+	}
+	var queries []string
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			queries = append(queries, req.URL.RawQuery)
+			return jsonResponse(200, pages[req.URL.Query().Get("starting_after")]), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("filters by updated_at across pages", func(t *testing.T) {
+		queries = nil
+		var ids []string
+		for customer, err := range client.CustomersModifiedSince(context.Background(), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ids = append(ids, customer.Id)
+		}
+		if got := strings.Join(ids, ","); got != "cus_1,cus_3" {
+			t.Errorf("Customers incorrect. Got: %s, Expected: cus_1,cus_3", got)
+		}
+		expected := "limit=100 limit=100&starting_after=cus_2"
+		if got := strings.Join(queries, " "); got != expected {
+			t.Errorf("Queries incorrect. Got: %s, Expected: %s", got, expected)
+		}
+	})
+
+	t.Run("stops paging when the caller breaks", func(t *testing.T) {
+		queries = nil
+		for range client.CustomersModifiedSince(context.Background(), time.Time{}) {
+			break
+		}
+		if len(queries) != 1 {
+			t.Errorf("Request count incorrect. Got: %d, Expected: 1", len(queries))
+		}
+	})
+}
+
+func TestCustomersIterate(t *testing.T) {
+	customer := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`, id)
+	}
+	list := func(hasMore bool, items ...string) string {
+		return fmt.Sprintf(`{"object":"list","url":"/v2/customers","has_more":%t,"data":[%s]}`, hasMore, strings.Join(items, ","))
+	}
+	newClient := func(t *testing.T, pages map[string]*http.Response) (*ClientWithResponses, *[]string) {
+		t.Helper()
+		var queries []string
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				queries = append(queries, req.URL.RawQuery)
+				resp, ok := pages[req.URL.Query().Get("starting_after")]
+				if !ok {
+					t.Fatalf("Unexpected request: %s", req.URL.RawQuery)
+				}
+				return resp, nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client, &queries
+	}
+	limit := 2
+	params := &GetAllCustomersParams{Limit: &limit}
+
+	t.Run("pages until an empty page", func(t *testing.T) {
+		client, queries := newClient(t, map[string]*http.Response{
+			"":      jsonResponse(200, list(true, customer("cus_1"), customer("cus_2"))),
+			"cus_2": jsonResponse(200, list(true, customer("cus_3"), customer("cus_4"))),
+			"cus_4": jsonResponse(200, list(false)),
+		})
+
+		ctx := context.Background()
+		it := Customers(client).Iterate(ctx, params)
+		var ids []string
+		for it.Next(ctx) {
+			ids = append(ids, it.Item().Id)
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := strings.Join(ids, ","); got != "cus_1,cus_2,cus_3,cus_4" {
+			t.Errorf("Customers incorrect. Got: %s, Expected: cus_1,cus_2,cus_3,cus_4", got)
+		}
+		expected := "limit=2 limit=2&starting_after=cus_2 limit=2&starting_after=cus_4"
+		if got := strings.Join(*queries, " "); got != expected {
+			t.Errorf("Queries incorrect. Got: %s, Expected: %s", got, expected)
+		}
+		if it.Next(ctx) {
+			t.Error("Expected Next to keep returning false")
+		}
+	})
+
+	t.Run("stops after a short page", func(t *testing.T) {
+		client, queries := newClient(t, map[string]*http.Response{
+			"": jsonResponse(200, list(true, customer("cus_1"))),
+		})
+
+		ctx := context.Background()
+		it := Customers(client).Iterate(ctx, params)
+		count := 0
+		for it.Next(ctx) {
+			count++
+		}
+		if it.Err() != nil || count != 1 || len(*queries) != 1 {
+			t.Errorf("Iteration incorrect. Got: %d items, %d requests, %v, Expected: 1 item, 1 request, no error", count, len(*queries), it.Err())
+		}
+	})
+
+	t.Run("propagates API errors", func(t *testing.T) {
+		client, _ := newClient(t, map[string]*http.Response{
+			"":      jsonResponse(200, list(true, customer("cus_1"), customer("cus_2"))),
+			"cus_2": jsonResponse(500, `{"status":500,"title":"Internal Server Error","type":"about:blank"}`),
+		})
+
+		ctx := context.Background()
+		it := Customers(client).Iterate(ctx, params)
+		count := 0
+		for it.Next(ctx) {
+			count++
+		}
+		var apiErr *APIError
+		if !errors.As(it.Err(), &apiErr) || apiErr.StatusCode != 500 {
+			t.Errorf("Expected 500 APIError, got: %v", it.Err())
+		}
+		if count != 2 {
+			t.Errorf("Item count incorrect. Got: %d, Expected: 2", count)
+		}
+	})
+
+	t.Run("stops when the context is canceled mid-iteration", func(t *testing.T) {
+		client, queries := newClient(t, map[string]*http.Response{
+			"": jsonResponse(200, list(true, customer("cus_1"), customer("cus_2"))),
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		it := Customers(client).Iterate(ctx, params)
+		count := 0
+		for it.Next(ctx) {
+			count++
+			cancel()
+		}
+		if !errors.Is(it.Err(), context.Canceled) {
+			t.Errorf("Expected context.Canceled, got: %v", it.Err())
+		}
+		if count != 2 || len(*queries) != 1 {
+			t.Errorf("Iteration incorrect. Got: %d items, %d requests, Expected: 2 items, 1 request", count, len(*queries))
+		}
+	})
+}
+
+func TestUpsertCustomerByEmail(t *testing.T) {
+	customer := func(id, email string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"customer","email":%q,"livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`, id, email)
+	}
+	type request struct {
+		method, path, body string
+	}
+	newClient := func(t *testing.T, customers ...string) (*ClientWithResponses, *[]request) {
+		t.Helper()
+		var requests []request
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				var body []byte
+				if req.Body != nil {
+					body, _ = io.ReadAll(req.Body)
+				}
+				requests = append(requests, request{req.Method, req.URL.Path, string(body)})
+				switch {
+				case req.Method == http.MethodGet:
+					return jsonResponse(200, `{"object":"list","url":"/v2/customers","has_more":false,"data":[`+strings.Join(customers, ",")+`]}`), nil
+				case req.URL.Path == "/v2/customers":
+					return jsonResponse(200, customer("cus_new", "taro@example.com")), nil
+				default:
+					return jsonResponse(200, customer(strings.TrimPrefix(req.URL.Path, "/v2/customers/"), "taro@example.com")), nil
+				}
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client, &requests
+	}
+	description := "VIP"
+
+	t.Run("creates a customer when none has the email", func(t *testing.T) {
+		client, requests := newClient(t, customer("cus_1", "hanako@example.com"))
+		var metadata CustomerUpdateRequest_Metadata_AdditionalProperties
+		if err := metadata.FromCustomerUpdateRequestMetadata0("gold"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		req := CustomerUpdateRequest{
+			Description: &description,
+			Metadata:    &map[string]CustomerUpdateRequest_Metadata_AdditionalProperties{"plan": metadata},
+		}
+
+		result, created, err := client.UpsertCustomerByEmail(context.Background(), "taro@example.com", req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !created || result.Id != "cus_new" {
+			t.Errorf("Result incorrect. Got: %s, created=%v, Expected: cus_new, created=true", result.Id, created)
+		}
+		last := (*requests)[len(*requests)-1]
+		expected := request{http.MethodPost, "/v2/customers", `{"description":"VIP","email":"taro@example.com","metadata":{"plan":"gold"}}`}
+		if last != expected {
+			t.Errorf("Create request incorrect. Got: %+v, Expected: %+v", last, expected)
+		}
+	})
+
+	t.Run("updates the customer with the email", func(t *testing.T) {
+		client, requests := newClient(t, customer("cus_1", "hanako@example.com"), customer("cus_2", "Taro@Example.com"))
+
+		result, created, err := client.UpsertCustomerByEmail(context.Background(), "taro@example.com", CustomerUpdateRequest{Description: &description})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if created || result.Id != "cus_2" {
+			t.Errorf("Result incorrect. Got: %s, created=%v, Expected: cus_2, created=false", result.Id, created)
+		}
+		last := (*requests)[len(*requests)-1]
+		expected := request{http.MethodPost, "/v2/customers/cus_2", `{"description":"VIP","email":"taro@example.com"}`}
+		if last != expected {
+			t.Errorf("Update request incorrect. Got: %+v, Expected: %+v", last, expected)
+		}
+		if strings.Contains(last.body, "default_payment_method_id") {
+			t.Errorf("Update request must not clear the default payment method. Got: %s", last.body)
+		}
+	})
+
+	t.Run("sends a set default payment method on update", func(t *testing.T) {
+		client, requests := newClient(t, customer("cus_1", "taro@example.com"))
+		pmID := "pm_1"
+
+		if _, _, err := client.UpsertCustomerByEmail(context.Background(), "taro@example.com", CustomerUpdateRequest{DefaultPaymentMethodId: &pmID}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		last := (*requests)[len(*requests)-1]
+		if last.body != `{"default_payment_method_id":"pm_1","email":"taro@example.com"}` {
+			t.Errorf("Update body incorrect. Got: %s", last.body)
+		}
+	})
+
+	t.Run("rejects an ambiguous email", func(t *testing.T) {
+		client, requests := newClient(t, customer("cus_1", "taro@example.com"), customer("cus_2", "taro@example.com"))
+
+		_, _, err := client.UpsertCustomerByEmail(context.Background(), "taro@example.com", CustomerUpdateRequest{})
+		if !errors.Is(err, ErrAmbiguousCustomer) || !strings.Contains(err.Error(), "cus_1, cus_2") {
+			t.Errorf("Expected ErrAmbiguousCustomer listing both ids, got: %v", err)
+		}
+		for _, r := range *requests {
+			if r.method != http.MethodGet {
+				t.Errorf("Unexpected write request: %+v", r)
+			}
+		}
+	})
+}
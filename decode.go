@@ -0,0 +1,94 @@
+package payjpv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// decodeJSON unmarshals b into v, decoding numbers in free-form values (interface{} and
+// map[string]interface{} fields) as json.Number rather than float64, so large integers such
+// as IDs or amounts inside event data survive without precision loss.
+// Use NumberInt64 and NumberFloat64 to read them.
+func decodeJSON(b []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	return nil
+}
+
+// NumberInt64 returns a free-form JSON number as an int64.
+// It accepts json.Number (as decoded by the SDK) as well as Go numeric types.
+func NumberInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case float64:
+		if n != float64(int64(n)) {
+			return 0, false
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// NumberFloat64 returns a free-form JSON number as a float64.
+// It accepts json.Number (as decoded by the SDK) as well as Go numeric types.
+func NumberFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// DecodeInto decodes the raw success body of a generated response into target, e.g. a caller-defined
+// struct that embeds the generated type and adds fields the SDK does not model yet.
+// API errors are returned as *APIError, as with Extract. Free-form numbers decode as json.Number.
+// Types with a generated UnmarshalJSON (see amounts.gen.go) take over decoding when embedded,
+// so decode those into a separate target instead.
+//
+// Example usage:
+//
+//	var customer struct {
+//	    payjpv2.CustomerResponse
+//	    Nickname string `json:"nickname"`
+//	}
+//	resp, err := client.GetCustomerWithResponse(ctx, customerID)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := payjpv2.DecodeInto(resp, &customer); err != nil {
+//	    return err
+//	}
+func DecodeInto(resp any, target any) error {
+	if apiErr := ParseAPIError(resp); apiErr != nil {
+		return apiErr
+	}
+	body, err := responseBody(resp)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return errors.New("response has no body")
+	}
+	return decodeJSON(body, target)
+}
@@ -0,0 +1,126 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFreeFormNumberDecoding(t *testing.T) {
+	body := `{"id":"evnt_1","type":"customer.updated","data":{"object":"customer","metadata":{"external_id":9007199254740993,"ratio":0.5}}}`
+
+	var event EventResponse
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+
+	metadata, ok := event.Data["metadata"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected metadata map, got: %T", event.Data["metadata"])
+	}
+	if _, ok := metadata["external_id"].(json.Number); !ok {
+		t.Fatalf("Expected json.Number, got: %T", metadata["external_id"])
+	}
+
+	id, ok := NumberInt64(metadata["external_id"])
+	if !ok || id != 9007199254740993 {
+		t.Errorf("NumberInt64() = %d, %v, want 9007199254740993, true", id, ok)
+	}
+	ratio, ok := NumberFloat64(metadata["ratio"])
+	if !ok || ratio != 0.5 {
+		t.Errorf("NumberFloat64() = %v, %v, want 0.5, true", ratio, ok)
+	}
+	if _, ok := NumberInt64(metadata["ratio"]); ok {
+		t.Error("Expected NumberInt64() to reject a fractional number")
+	}
+	if _, ok := NumberInt64("9007199254740993"); ok {
+		t.Error("Expected NumberInt64() to reject a string")
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	body := `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{},"nickname":"taro","loyalty":{"points":120}}`
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(200, body), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("decodes into a custom struct with extra fields", func(t *testing.T) {
+		resp, err := client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var customer struct {
+			CustomerResponse
+			Nickname string `json:"nickname"`
+			Loyalty  struct {
+				Points int `json:"points"`
+			} `json:"loyalty"`
+		}
+		if err := DecodeInto(resp, &customer); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if customer.Id != "cus_1" {
+			t.Errorf("ID incorrect. Got: %s, Expected: cus_1", customer.Id)
+		}
+		if customer.Nickname != "taro" || customer.Loyalty.Points != 120 {
+			t.Errorf("Extra fields incorrect. Got: %+v", customer)
+		}
+	})
+
+	t.Run("decodes a 202 Accepted body", func(t *testing.T) {
+		accepted, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(202, body), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		resp, err := Extract(accepted.GetCustomerWithResponse(context.Background(), "cus_1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.StatusCode() != 202 {
+			t.Errorf("Status code incorrect. Got: %d, Expected: 202", resp.StatusCode())
+		}
+		var customer CustomerResponse
+		if err := DecodeInto(resp, &customer); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if customer.Id != "cus_1" {
+			t.Errorf("ID incorrect. Got: %s, Expected: cus_1", customer.Id)
+		}
+	})
+
+	t.Run("returns API errors", func(t *testing.T) {
+		resp := &GetCustomerResponse{
+			Body:         []byte(`{"status":404,"title":"Not Found","type":"about:blank"}`),
+			HTTPResponse: &http.Response{StatusCode: 404},
+			NotFound:     &ErrorResponse{Status: 404, Title: "Not Found"},
+		}
+		var target map[string]any
+		var apiErr *APIError
+		if err := DecodeInto(resp, &target); !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+			t.Errorf("Expected not found APIError, got: %v", err)
+		}
+	})
+
+	t.Run("rejects values without a body", func(t *testing.T) {
+		var target map[string]any
+		if err := DecodeInto(struct{}{}, &target); err == nil {
+			t.Error("Expected error, got nil")
+		}
+		if err := DecodeInto(&GetCustomerResponse{HTTPResponse: &http.Response{StatusCode: 200}}, &target); err == nil {
+			t.Error("Expected error for empty body, got nil")
+		}
+	})
+}
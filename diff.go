@@ -0,0 +1,85 @@
+package payjpv2
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType         = reflect.TypeFor[time.Time]()
+	httpResponseType = reflect.TypeFor[*http.Response]()
+)
+
+// Diff compares two fetches of the same resource and returns the paths of the fields that differ,
+// mapped to their value in b. Paths are made of the JSON names of the fields joined with dots,
+// e.g. "email" or "billing_details.address.city"; nested structs are compared field by field and
+// other values, including maps and slices, as a whole. Unexported fields and the raw HTTP fields
+// of the generated responses (HTTPResponse and Body) are skipped. A nil a or b compares as the
+// zero value, and an empty map means nothing changed.
+//
+// Example usage:
+//
+//	changes := payjpv2.Diff(previous, current)
+//	if email, ok := changes["email"]; ok {
+//	    notifyEmailChange(current.Id, email)
+//	}
+func Diff[T any](a, b *T) map[string]any {
+	var zero T
+	if a == nil {
+		a = &zero
+	}
+	if b == nil {
+		b = &zero
+	}
+	changes := map[string]any{}
+	diffValues(changes, "", reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem())
+	return changes
+}
+
+// diffValues records in changes the differences between a and b found under path
+func diffValues(changes map[string]any, path string, a, b reflect.Value) {
+	switch {
+	case a.Type() == timeType:
+		if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+			changes[path] = b.Interface()
+		}
+	case a.Kind() == reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() || isHTTPField(field) {
+				continue
+			}
+			diffValues(changes, joinPath(path, fieldName(field)), a.Field(i), b.Field(i))
+		}
+	case a.Kind() == reflect.Pointer && a.Type().Elem().Kind() == reflect.Struct && !a.IsNil() && !b.IsNil():
+		diffValues(changes, path, a.Elem(), b.Elem())
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			changes[path] = b.Interface()
+		}
+	}
+}
+
+// isHTTPField reports whether field is the raw HTTP response or body of a generated response
+func isHTTPField(field reflect.StructField) bool {
+	return field.Type == httpResponseType || (field.Name == "Body" && field.Type == reflect.TypeFor[[]byte]())
+}
+
+// fieldName returns the JSON name of field, or its Go name if it has none
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// joinPath appends name to the dotted path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,66 @@
+package payjpv2
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	decode := func(t *testing.T, body string) *CustomerResponse {
+		t.Helper()
+		var customer CustomerResponse
+		if err := json.Unmarshal([]byte(body), &customer); err != nil {
+			t.Fatalf("Failed to decode customer: %v", err)
+		}
+		return &customer
+	}
+
+	t.Run("reports the changed field", func(t *testing.T) {
+		a := decode(t, `{"id":"cus_1","object":"customer","email":"a@example.com","description":"old","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`)
+		b := decode(t, `{"id":"cus_1","object":"customer","email":"a@example.com","description":"new","livemode":false,"created_at":"2024-01-01T09:00:00+09:00","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`)
+
+		changes := Diff(a, b)
+		if len(changes) != 1 {
+			t.Fatalf("Changes incorrect. Got: %v, Expected: description only", changes)
+		}
+		if description, ok := changes["description"].(*string); !ok || *description != "new" {
+			t.Errorf("Description incorrect. Got: %#v, Expected: new", changes["description"])
+		}
+	})
+
+	t.Run("returns no changes for equal values", func(t *testing.T) {
+		body := `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`
+		if changes := Diff(decode(t, body), decode(t, body)); len(changes) != 0 {
+			t.Errorf("Expected no changes, got: %v", changes)
+		}
+	})
+
+	t.Run("uses nested paths and skips HTTP fields", func(t *testing.T) {
+		a := &GetCustomerResponse{
+			Body:         []byte(`{"id":"cus_1"}`),
+			HTTPResponse: &http.Response{StatusCode: 200},
+			Result:       &CustomerResponse{Id: "cus_1", UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		b := &GetCustomerResponse{
+			Body:         []byte(`{"id":"cus_1","livemode":true}`),
+			HTTPResponse: &http.Response{StatusCode: 304},
+			Result:       &CustomerResponse{Id: "cus_1", UpdatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		changes := Diff(a, b)
+		if len(changes) != 1 {
+			t.Fatalf("Changes incorrect. Got: %v, Expected: Result.updated_at only", changes)
+		}
+		if _, ok := changes["Result.updated_at"]; !ok {
+			t.Errorf("Expected Result.updated_at to change, got: %v", changes)
+		}
+	})
+
+	t.Run("compares nil as the zero value", func(t *testing.T) {
+		changes := Diff(nil, &CustomerResponse{Id: "cus_1"})
+		if changes["id"] != "cus_1" || len(changes) != 1 {
+			t.Errorf("Changes incorrect. Got: %v, Expected: id only", changes)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// namedEditor is a RequestEditorFn registered with WithNamedRequestEditor
+type namedEditor struct {
+	name string
+	fn   RequestEditorFn
+}
+
+// namedEditors holds a client's named request editors, which can be removed at runtime
+type namedEditors struct {
+	mu      sync.RWMutex
+	editors []namedEditor
+}
+
+// set registers fn under name, replacing an editor with the same name in place
+func (n *namedEditors) set(name string, fn RequestEditorFn) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := range n.editors {
+		if n.editors[i].name == name {
+			n.editors[i].fn = fn
+			return
+		}
+	}
+	n.editors = append(n.editors, namedEditor{name: name, fn: fn})
+}
+
+// remove unregisters the editor named name and reports whether there was one
+func (n *namedEditors) remove(name string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := range n.editors {
+		if n.editors[i].name == name {
+			n.editors = slices.Delete(n.editors, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the names of the registered editors in the order they run
+func (n *namedEditors) names() []string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	names := make([]string, len(n.editors))
+	for i, e := range n.editors {
+		names[i] = e.name
+	}
+	return names
+}
+
+// apply runs the registered editors in order
+func (n *namedEditors) apply(ctx context.Context, req *http.Request) error {
+	n.mu.RLock()
+	editors := slices.Clone(n.editors)
+	n.mu.RUnlock()
+	for _, e := range editors {
+		if err := e.fn(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithNamedRequestEditor returns a ClientOption that adds fn as a request editor registered under
+// name, so it can be removed later with RemoveEditor. Registering a name again replaces its editor.
+// Named editors run in registration order, at the position of the first WithNamedRequestEditor
+// among the client's other editors.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithNamedRequestEditor("debug", func(ctx context.Context, req *http.Request) error {
+//	        req.Header.Set("X-Debug", "1")
+//	        return nil
+//	    }),
+//	)
+//	...
+//	client.RemoveEditor("debug")
+func WithNamedRequestEditor(name string, fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		if name == "" {
+			return errors.New("editor name cannot be empty")
+		}
+		if fn == nil {
+			return errors.New("request editor cannot be nil")
+		}
+		d := sdkDoerFor(c)
+		if d.editors == nil {
+			d.editors = &namedEditors{}
+			c.RequestEditors = append(c.RequestEditors, d.editors.apply)
+		}
+		d.editors.set(name, fn)
+		return nil
+	}
+}
+
+// namedEditorsOf returns the named editors of c, or nil if it has none
+func (c *ClientWithResponses) namedEditorsOf() *namedEditors {
+	client, ok := c.ClientInterface.(*Client)
+	if !ok {
+		return nil
+	}
+	d, ok := client.Client.(*sdkDoer)
+	if !ok {
+		return nil
+	}
+	return d.editors
+}
+
+// RemoveEditor removes the request editor registered under name with WithNamedRequestEditor and
+// reports whether there was one. Requests sent after it returns no longer run the editor.
+func (c *ClientWithResponses) RemoveEditor(name string) bool {
+	editors := c.namedEditorsOf()
+	return editors != nil && editors.remove(name)
+}
+
+// EditorNames returns the names of the request editors registered with WithNamedRequestEditor,
+// in the order they run.
+func (c *ClientWithResponses) EditorNames() []string {
+	editors := c.namedEditorsOf()
+	if editors == nil {
+		return nil
+	}
+	return editors.names()
+}
@@ -0,0 +1,88 @@
+package payjpv2
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithNamedRequestEditor(t *testing.T) {
+	setHeader := func(name, value string) RequestEditorFn {
+		return func(ctx context.Context, req *http.Request) error {
+			req.Header.Set(name, value)
+			return nil
+		}
+	}
+
+	t.Run("removes an editor at runtime", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithNamedRequestEditor("debug", setHeader("X-Debug", "1")),
+			WithNamedRequestEditor("tenant", setHeader("X-Tenant", "acme")),
+			WithHTTPClient(&http.Client{Transport: mockTransport}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if names := strings.Join(client.EditorNames(), ","); names != "debug,tenant" {
+			t.Errorf("Editor names incorrect. Got: %s, Expected: debug,tenant", names)
+		}
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if got := mockTransport.capturedHeaders.Get("X-Debug"); got != "1" {
+			t.Errorf("X-Debug header incorrect. Got: %s, Expected: 1", got)
+		}
+
+		if !client.RemoveEditor("debug") {
+			t.Error("Expected RemoveEditor to report a removed editor")
+		}
+		if client.RemoveEditor("debug") {
+			t.Error("Expected RemoveEditor to report no editor the second time")
+		}
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if values := mockTransport.capturedHeaders.Values("X-Debug"); len(values) != 0 {
+			t.Errorf("Expected no X-Debug header, got: %v", values)
+		}
+		if got := mockTransport.capturedHeaders.Get("X-Tenant"); got != "acme" {
+			t.Errorf("X-Tenant header incorrect. Got: %s, Expected: acme", got)
+		}
+	})
+
+	t.Run("registering a name again replaces its editor", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithHTTPClient(&http.Client{Transport: mockTransport}),
+			WithNamedRequestEditor("debug", setHeader("X-Debug", "1")),
+			WithNamedRequestEditor("debug", setHeader("X-Debug", "2")),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if got := mockTransport.capturedHeaders.Get("X-Debug"); got != "2" {
+			t.Errorf("X-Debug header incorrect. Got: %s, Expected: 2", got)
+		}
+		if names := client.EditorNames(); len(names) != 1 {
+			t.Errorf("Editor names incorrect. Got: %v, Expected: [debug]", names)
+		}
+	})
+
+	t.Run("RemoveEditor without named editors", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_key")
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if client.RemoveEditor("debug") {
+			t.Error("Expected RemoveEditor to report no editor")
+		}
+	})
+
+	t.Run("rejects an empty name", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithNamedRequestEditor("", setHeader("X-Debug", "1"))); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// emailBodies maps the operations whose request body has email typed fields to the body type.
+// A * in the path matches one path segment.
+var emailBodies = map[string]reflect.Type{
+	"POST /v2/customers":         reflect.TypeFor[CustomerCreateRequest](),
+	"POST /v2/customers/*":       reflect.TypeFor[CustomerUpdateRequest](),
+	"POST /v2/checkout/sessions": reflect.TypeFor[CheckoutSessionCreateRequest](),
+}
+
+// NormalizeEmail trims surrounding whitespace from email and lowercases it.
+//
+// Example usage:
+//
+//	email := payjpv2.NormalizeEmail(form.Get("email"))
+//	req := payjpv2.CustomerCreateRequest{Email: &email}
+func NormalizeEmail(email string) openapi_types.Email {
+	return openapi_types.Email(strings.ToLower(strings.TrimSpace(email)))
+}
+
+// WithEmailNormalization returns a ClientOption that applies NormalizeEmail to the email typed
+// fields of outgoing request bodies, such as the email of a customer or the customer_email of a
+// checkout session. Other fields, including the string typed email of billing details, are sent
+// as is.
+//
+// The typed methods validate emails before sending, so an email with surrounding whitespace is
+// only accepted by the WithBody methods; normalize it with NormalizeEmail for the typed ones.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey, payjpv2.WithEmailNormalization())
+func WithEmailNormalization() ClientOption {
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		names := emailFieldsOf(req)
+		if len(names) == 0 {
+			return nil
+		}
+		return rewriteJSONBody(req, func(fields map[string]json.RawMessage) bool {
+			return normalizeEmailFields(fields, names)
+		})
+	})
+}
+
+// emailFieldsOf returns the JSON names of the email typed fields of req's body type, if known
+func emailFieldsOf(req *http.Request) []string {
+	for operation, bodyType := range emailBodies {
+		if matchOperation(req, operation) {
+			return emailFieldNames(bodyType)
+		}
+	}
+	return nil
+}
+
+// emailFieldNames returns the JSON names of the fields of t with type Email or *Email
+func emailFieldNames(t reflect.Type) []string {
+	emailType := reflect.TypeFor[openapi_types.Email]()
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType != emailType {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// normalizeEmailFields applies NormalizeEmail to the named string fields and reports whether
+// any of them changed
+func normalizeEmailFields(fields map[string]json.RawMessage, names []string) bool {
+	changed := false
+	for _, name := range names {
+		var email string
+		if raw, ok := fields[name]; !ok || json.Unmarshal(raw, &email) != nil {
+			continue
+		}
+		normalized := NormalizeEmail(email)
+		if string(normalized) == email {
+			continue
+		}
+		raw, err := json.Marshal(string(normalized))
+		if err != nil {
+			continue
+		}
+		fields[name] = raw
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,81 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := NormalizeEmail("  Taro@Example.COM \n"); got != "taro@example.com" {
+		t.Errorf("NormalizeEmail incorrect. Got: %q, Expected: taro@example.com", got)
+	}
+}
+
+func TestWithEmailNormalization(t *testing.T) {
+	newClient := func(t *testing.T, sent *map[string]any) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithEmailNormalization(), WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				data, _ := io.ReadAll(req.Body)
+				if int64(len(data)) != req.ContentLength {
+					t.Errorf("Content length incorrect. Got: %d, Expected: %d", req.ContentLength, len(data))
+				}
+				*sent = nil
+				if err := json.Unmarshal(data, sent); err != nil {
+					t.Errorf("Failed to decode sent body %s: %v", data, err)
+				}
+				return jsonResponse(200, `{}`), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("normalizes a whitespaced uppercase email", func(t *testing.T) {
+		var sent map[string]any
+		body := `{"email":"  Taro@Example.COM ","description":"Taro YAMADA"}`
+		_, err := newClient(t, &sent).CreateCustomerWithBodyWithResponse(context.Background(), "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if sent["email"] != "taro@example.com" {
+			t.Errorf("Email incorrect. Got: %v, Expected: taro@example.com", sent["email"])
+		}
+		if sent["description"] != "Taro YAMADA" {
+			t.Errorf("Description incorrect. Got: %v, Expected: Taro YAMADA", sent["description"])
+		}
+	})
+
+	t.Run("normalizes typed request bodies", func(t *testing.T) {
+		var sent map[string]any
+		email := openapi_types.Email("Hanako@Example.com")
+		_, err := newClient(t, &sent).UpdateCustomerWithResponse(context.Background(), "cus_1", CustomerUpdateRequest{Email: &email})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if sent["email"] != "hanako@example.com" {
+			t.Errorf("Email incorrect. Got: %v, Expected: hanako@example.com", sent["email"])
+		}
+	})
+
+	t.Run("leaves string typed emails untouched", func(t *testing.T) {
+		var sent map[string]any
+		body := `{"type":"card","billing_details":{"email":"Taro@Example.COM"},"email":"Taro@Example.COM"}`
+		_, err := newClient(t, &sent).CreatePaymentMethodWithBodyWithResponse(context.Background(), "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		details, _ := sent["billing_details"].(map[string]any)
+		if details["email"] != "Taro@Example.COM" || sent["email"] != "Taro@Example.COM" {
+			t.Errorf("Body changed. Got: %v", sent)
+		}
+	})
+}
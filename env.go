@@ -0,0 +1,51 @@
+package payjpv2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+const (
+	// EnvAPIKey is the environment variable read by NewFromEnv for the API key
+	EnvAPIKey = "PAYJP_API_KEY"
+	// EnvAPIHost is the optional environment variable read by NewFromEnv for the API base URL
+	EnvAPIHost = "PAYJP_API_HOST"
+)
+
+// NewFromEnv creates a new PAY.JP V2 client from the PAYJP_API_KEY and optional PAYJP_API_HOST
+// environment variables. The returned error lists every missing or invalid variable.
+// opts are applied after the base URL, so they can still override it.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewFromEnv()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewFromEnv(opts ...ClientOption) (*ClientWithResponses, error) {
+	var errs []error
+
+	apiKey := os.Getenv(EnvAPIKey)
+	if err := validateAPIKey(apiKey); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", EnvAPIKey, err))
+	}
+
+	apiHost := os.Getenv(EnvAPIHost)
+	if apiHost != "" {
+		u, err := url.Parse(apiHost)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s: invalid URL %q: must be an absolute http(s) URL", EnvAPIHost, apiHost))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid environment: %w", errors.Join(errs...))
+	}
+
+	if apiHost != "" {
+		opts = append([]ClientOption{WithBaseURL(apiHost)}, opts...)
+	}
+	return NewPayjpClientWithResponses(apiKey, opts...)
+}
@@ -0,0 +1,59 @@
+package payjpv2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Run("creates a client from the environment", func(t *testing.T) {
+		t.Setenv("PAYJP_API_KEY", "sk_test_example")
+		t.Setenv("PAYJP_API_HOST", "https://api.example.com")
+
+		client, err := NewFromEnv()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := client.ClientInterface.(*Client).Server; got != "https://api.example.com/" {
+			t.Errorf("Server incorrect. Got: %s, Expected: https://api.example.com/", got)
+		}
+	})
+
+	t.Run("uses the default host when unset", func(t *testing.T) {
+		t.Setenv("PAYJP_API_KEY", "sk_test_example")
+		t.Setenv("PAYJP_API_HOST", "")
+
+		client, err := NewFromEnv()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := client.ClientInterface.(*Client).Server; got != "https://api.pay.jp/" {
+			t.Errorf("Server incorrect. Got: %s, Expected: https://api.pay.jp/", got)
+		}
+	})
+
+	t.Run("reports a missing API key", func(t *testing.T) {
+		t.Setenv("PAYJP_API_KEY", "")
+		t.Setenv("PAYJP_API_HOST", "")
+
+		_, err := NewFromEnv()
+		if err == nil || !strings.Contains(err.Error(), "PAYJP_API_KEY: API key cannot be empty") {
+			t.Errorf("Expected missing PAYJP_API_KEY error, got: %v", err)
+		}
+	})
+
+	t.Run("lists every invalid variable", func(t *testing.T) {
+		t.Setenv("PAYJP_API_KEY", "pk_test_public")
+		t.Setenv("PAYJP_API_HOST", "api.pay.jp")
+
+		_, err := NewFromEnv()
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		for _, expected := range []string{"PAYJP_API_KEY: invalid API key format", `PAYJP_API_HOST: invalid URL "api.pay.jp"`} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Error missing %q: %s", expected, err.Error())
+			}
+		}
+	})
+}
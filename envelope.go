@@ -0,0 +1,50 @@
+package payjpv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithErrorEnvelopeDetection returns a ClientOption that treats 2xx responses whose body is an
+// error envelope (a problem+json object with an error status and a title) as the error they
+// describe, so Extract and ParseAPIError return an *APIError. Some misconfigured gateways
+// answer errors this way. It is off by default.
+func WithErrorEnvelopeDetection() ClientOption {
+	return func(c *Client) error {
+		sdkDoerFor(c).detectErrorEnvelope = true
+		return nil
+	}
+}
+
+// unwrapErrorEnvelope rewrites a 2xx response carrying an error envelope to the envelope's status
+func unwrapErrorEnvelope(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var envelope struct {
+		Status *int    `json:"status"`
+		Title  *string `json:"title"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Status == nil || envelope.Title == nil {
+		return resp, nil
+	}
+	if status := *envelope.Status; status >= 400 && status <= 599 {
+		resp.StatusCode = status
+		resp.Status = fmt.Sprintf("%d %s", status, http.StatusText(status))
+		if resp.Header == nil {
+			resp.Header = make(http.Header)
+		}
+		resp.Header.Set("Content-Type", "application/problem+json")
+	}
+	return resp, nil
+}
@@ -0,0 +1,62 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWithErrorEnvelopeDetection(t *testing.T) {
+	envelope := `{"status":404,"title":"Not Found","detail":"No such customer: 'cus_1'","type":"about:blank"}`
+	newClient := func(t *testing.T, body string, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(200, body), nil
+			}),
+		})}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("converts a 200 error envelope into an APIError", func(t *testing.T) {
+		client := newClient(t, envelope, WithErrorEnvelopeDetection())
+
+		_, err := Extract(client.GetCustomerWithResponse(context.Background(), "cus_1"))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+			t.Fatalf("Expected not found APIError, got: %v", err)
+		}
+		if apiErr.Body == nil || apiErr.Body.Detail == nil || *apiErr.Body.Detail != "No such customer: 'cus_1'" {
+			t.Errorf("Error body incorrect. Got: %+v", apiErr.Body)
+		}
+	})
+
+	t.Run("leaves successful bodies alone", func(t *testing.T) {
+		client := newClient(t, `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`, WithErrorEnvelopeDetection())
+
+		resp, err := Extract(client.GetCustomerWithResponse(context.Background(), "cus_1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Result == nil || resp.Result.Id != "cus_1" {
+			t.Errorf("Result incorrect. Got: %+v", resp.Result)
+		}
+	})
+
+	t.Run("is off by default", func(t *testing.T) {
+		client := newClient(t, envelope)
+
+		resp, err := client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.StatusCode() != 200 || ParseAPIError(resp) != nil {
+			t.Errorf("Expected the envelope to be passed through, got status %d", resp.StatusCode())
+		}
+	})
+}
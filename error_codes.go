@@ -0,0 +1,45 @@
+package payjpv2
+
+// ErrorCode is the machine-readable code of a PAY.JP error response, read into APIError.Code.
+// The v2 ErrorResponse schema declares no error codes; the constants below are the codes of
+// the v1 API, so the v2 API may return other codes or none at all. Compare APIError.Code
+// against them only as a refinement of the status predicates such as IsUnprocessableEntity.
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidNumber is returned for an invalid card number
+	ErrorCodeInvalidNumber ErrorCode = "invalid_number"
+	// ErrorCodeInvalidCVC is returned for an invalid security code
+	ErrorCodeInvalidCVC ErrorCode = "invalid_cvc"
+	// ErrorCodeInvalidExpirationDate is returned for an invalid expiration date
+	ErrorCodeInvalidExpirationDate ErrorCode = "invalid_expiration_date"
+	// ErrorCodeIncorrectCardData is returned when the card number, expiration date or security
+	// code is incorrect
+	ErrorCodeIncorrectCardData ErrorCode = "incorrect_card_data"
+	// ErrorCodeExpiredCard is returned for an expired card
+	ErrorCodeExpiredCard ErrorCode = "expired_card"
+	// ErrorCodeCardDeclined is returned when the card issuer declined the payment
+	ErrorCodeCardDeclined ErrorCode = "card_declined"
+	// ErrorCodeProcessingError is returned when the payment could not be processed
+	ErrorCodeProcessingError ErrorCode = "processing_error"
+	// ErrorCodeUnacceptableBrand is returned for a card brand the account does not accept
+	ErrorCodeUnacceptableBrand ErrorCode = "unacceptable_brand"
+	// ErrorCodeInvalidAmount is returned for an amount out of the allowed range
+	ErrorCodeInvalidAmount ErrorCode = "invalid_amount"
+	// ErrorCodeInvalidCurrency is returned for an unsupported currency
+	ErrorCodeInvalidCurrency ErrorCode = "invalid_currency"
+	// ErrorCodeMissingParam is returned when a required parameter is missing
+	ErrorCodeMissingParam ErrorCode = "missing_param"
+	// ErrorCodeAlreadyRefunded is returned when refunding a payment that is fully refunded
+	ErrorCodeAlreadyRefunded ErrorCode = "already_refunded"
+	// ErrorCodeAlreadyCaptured is returned when capturing a payment that is already captured
+	ErrorCodeAlreadyCaptured ErrorCode = "already_captured"
+	// ErrorCodeThreeDSecureFailed is returned when 3-D Secure authentication failed
+	ErrorCodeThreeDSecureFailed ErrorCode = "three_d_secure_failed"
+	// ErrorCodeInvalidAPIKey is returned for an unknown or revoked API key
+	ErrorCodeInvalidAPIKey ErrorCode = "invalid_api_key"
+	// ErrorCodeNotFound is returned when the resource does not exist
+	ErrorCodeNotFound ErrorCode = "not_found"
+	// ErrorCodeOverCapacity is returned when the API is rate limiting requests
+	ErrorCodeOverCapacity ErrorCode = "over_capacity"
+)
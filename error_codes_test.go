@@ -0,0 +1,90 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected ErrorCode
+	}{
+		{
+			name:     "code member outside the schema",
+			status:   402,
+			body:     `{"status":402,"title":"Payment Required","type":"about:blank","code":"card_declined"}`,
+			expected: ErrorCodeCardDeclined,
+		},
+		{
+			name:     "problem type URI",
+			status:   402,
+			body:     `{"status":402,"title":"Payment Required","type":"https://example.com/problems/card_declined"}`,
+			expected: ErrorCodeCardDeclined,
+		},
+		{
+			name:     "code member takes precedence over the problem type",
+			status:   422,
+			body:     `{"status":422,"title":"Unprocessable Entity","type":"https://example.com/problems/validation","code":"invalid_amount"}`,
+			expected: ErrorCodeInvalidAmount,
+		},
+		{
+			name:   "relative problem type",
+			status: 422,
+			body:   `{"status":422,"title":"Unprocessable Entity","type":"validation"}`,
+		},
+		{
+			name:   "about:blank problem type",
+			status: 422,
+			body:   `{"status":422,"title":"Unprocessable Entity","type":"about:blank"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+				Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					resp := jsonResponse(tt.status, tt.body)
+					resp.Header.Set("Content-Type", "application/problem+json")
+					return resp, nil
+				}),
+			}))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			_, err = Extract(client.CreatePaymentFlowWithResponse(context.Background(), PaymentFlowCreateRequest{Amount: 1000}))
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected APIError, got: %v", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("Status code incorrect. Got: %d, Expected: %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Code != tt.expected {
+				t.Errorf("Code incorrect. Got: %q, Expected: %q", apiErr.Code, tt.expected)
+			}
+		})
+	}
+
+	t.Run("raw requests", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(402, `{"status":402,"title":"Payment Required","type":"about:blank","code":"card_declined"}`), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, _, err = client.DoRawMap(context.Background(), http.MethodPost, "/v2/payment_flows", map[string]any{"amount": 1000})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != ErrorCodeCardDeclined {
+			t.Errorf("Expected card_declined APIError, got: %v", err)
+		}
+	})
+}
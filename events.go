@@ -0,0 +1,261 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// eventTypes holds the data types registered with RegisterEventType, by event type
+var eventTypes sync.Map
+
+// RegisterEventType makes DecodeData decode the data of events of type typeName (e.g.
+// "customer.created") into the value returned by newFn, which must be a pointer. Registering
+// a type again replaces the previous registration. It panics if typeName is empty or newFn nil.
+//
+// Example usage:
+//
+//	payjpv2.RegisterEventType("customer.created", func() any { return new(payjpv2.CustomerResponse) })
+func RegisterEventType(typeName string, newFn func() any) {
+	if typeName == "" {
+		panic("payjpv2: RegisterEventType called with an empty event type")
+	}
+	if newFn == nil {
+		panic("payjpv2: RegisterEventType called with a nil constructor for " + typeName)
+	}
+	eventTypes.Store(typeName, newFn)
+}
+
+// DecodeData decodes the data of the event into the type registered for its type with
+// RegisterEventType. For unregistered types it returns the data as json.RawMessage.
+//
+// Example usage:
+//
+//	data, err := event.DecodeData()
+//	if err != nil {
+//	    return err
+//	}
+//	if customer, ok := data.(*payjpv2.CustomerResponse); ok {
+//	    fmt.Println(customer.Id)
+//	}
+func (e *EventResponse) DecodeData() (any, error) {
+	raw, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event data: %w", err)
+	}
+	newFn, ok := eventTypes.Load(e.Type)
+	if !ok {
+		return json.RawMessage(raw), nil
+	}
+	data := newFn.(func() any)()
+	if err := json.Unmarshal(raw, data); err != nil {
+		return nil, fmt.Errorf("failed to decode data of %s event: %w", e.Type, err)
+	}
+	return data, nil
+}
+
+// EventResult is the outcome of fetching one event with GetEventsByID.
+type EventResult struct {
+	// Event is the fetched event, nil if Err is set
+	Event *EventResponse
+	// Err is the error for the event, e.g. an *APIError with status 404 for an unknown ID
+	Err error
+}
+
+// EventResults holds the outcome of GetEventsByID for each requested event ID.
+type EventResults map[string]EventResult
+
+// Err returns a *BatchError with the failed events, or nil if every event was fetched.
+//
+// Example usage:
+//
+//	results := client.GetEventsByID(ctx, ids, 4)
+//	var apiErr *payjpv2.APIError
+//	if errors.As(results.Err(), &apiErr) && apiErr.IsNotFound() {
+//	    // at least one event does not exist
+//	}
+func (r EventResults) Err() error {
+	errs := make(map[string]error, len(r))
+	for id, result := range r {
+		errs[id] = result.Err
+	}
+	return NewBatchError(errs)
+}
+
+// GetEventsByID fetches the events with the given IDs, at most concurrency at a time, e.g. to
+// confirm the latest state of events during webhook reconciliation. The result has an entry for
+// every ID; a failure is reported in that ID's entry and does not stop the other fetches, and
+// Err on the result aggregates the failures into a *BatchError.
+// A concurrency below 1 fetches one event at a time.
+//
+// Example usage:
+//
+//	results := client.GetEventsByID(ctx, []string{"evnt_1", "evnt_2"}, 4)
+//	for id, result := range results {
+//	    if result.Err != nil {
+//	        log.Printf("event %s: %v", id, result.Err)
+//	        continue
+//	    }
+//	    fmt.Println(id, result.Event.Type)
+//	}
+func (c *ClientWithResponses) GetEventsByID(ctx context.Context, ids []string, concurrency int, reqEditors ...RequestEditorFn) EventResults {
+	concurrency = max(concurrency, 1)
+	results := make(EventResults, len(ids))
+	seen := make(map[string]bool, len(ids))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := EventResult{}
+			resp, err := Extract(c.GetEventWithResponse(ctx, id, reqEditors...))
+			switch {
+			case err != nil:
+				result.Err = err
+			case resp.Result == nil:
+				result.Err = errors.New("get event response has no result")
+			default:
+				result.Event = resp.Result
+			}
+
+			mu.Lock()
+			results[id] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// ErrEventModeMismatch is returned by ConstructEvent with WithEventModeCheck when a live mode
+// event is received by a test mode client, or the other way round.
+var ErrEventModeMismatch = errors.New("event mode does not match the client's key")
+
+// EventOption configures ConstructEvent.
+type EventOption func(*eventOptions)
+
+type eventOptions struct {
+	checkMode bool
+}
+
+// WithEventModeCheck makes ConstructEvent reject events whose livemode flag does not match the
+// type of the API key the client was created with, e.g. a test mode event delivered to a
+// production endpoint. The check fails for clients without a known key type, such as tenant
+// clients.
+func WithEventModeCheck() EventOption {
+	return func(o *eventOptions) {
+		o.checkMode = true
+	}
+}
+
+// withKeyType returns a ClientOption that records the type of the client's API key for
+// WithEventModeCheck
+func withKeyType(keyType KeyType) ClientOption {
+	return func(c *Client) error {
+		sdkDoerFor(c).keyType = keyType
+		return nil
+	}
+}
+
+// keyType returns the type of the API key c was created with, or KeyTypeUnknown
+func (c *ClientWithResponses) keyType() KeyType {
+	if client, ok := c.ClientInterface.(*Client); ok {
+		if d, ok := client.Client.(*sdkDoer); ok {
+			return d.keyType
+		}
+	}
+	return KeyTypeUnknown
+}
+
+// ConstructEvent decodes an event payload, such as the body of a webhook request. The payload
+// is not authenticated; fetch the event with GetEventWithResponse to confirm it if needed.
+//
+// Example usage:
+//
+//	event, err := client.ConstructEvent(body, payjpv2.WithEventModeCheck())
+//	if errors.Is(err, payjpv2.ErrEventModeMismatch) {
+//	    w.WriteHeader(http.StatusOK) // acknowledge, but ignore the event
+//	    return
+//	}
+func (c *ClientWithResponses) ConstructEvent(payload []byte, opts ...EventOption) (*EventResponse, error) {
+	var o eventOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var event EventResponse
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	if event.Id == "" || event.Type == "" {
+		return nil, errors.New("event has no id or type")
+	}
+	if !o.checkMode {
+		return &event, nil
+	}
+
+	var mode struct {
+		Livemode *bool `json:"livemode"`
+	}
+	if err := json.Unmarshal(payload, &mode); err != nil || mode.Livemode == nil {
+		return nil, errors.New("event has no livemode flag")
+	}
+	keyType := c.keyType()
+	if keyType == KeyTypeUnknown {
+		return nil, errors.New("cannot check the event mode: the client's key type is unknown")
+	}
+	if *mode.Livemode != (keyType == KeyTypeLive) {
+		eventMode := "test"
+		if *mode.Livemode {
+			eventMode = "live"
+		}
+		return nil, fmt.Errorf("%w: %s mode event %s received by a %s mode client", ErrEventModeMismatch, eventMode, event.Id, keyType)
+	}
+	return &event, nil
+}
+
+// maxEventPayloadBytes is the largest request body read by ConstructEventFromRequest
+const maxEventPayloadBytes = 1 << 20
+
+// ConstructEventFromRequest is like ConstructEvent for the body of a webhook request. The body
+// is read and closed.
+//
+// Example usage:
+//
+//	func handleWebhook(w http.ResponseWriter, r *http.Request) {
+//	    event, err := client.ConstructEventFromRequest(r, payjpv2.WithEventModeCheck())
+//	    if err != nil {
+//	        http.Error(w, err.Error(), http.StatusBadRequest)
+//	        return
+//	    }
+//	    fmt.Println(event.Type)
+//	}
+func (c *ClientWithResponses) ConstructEventFromRequest(r *http.Request, opts ...EventOption) (*EventResponse, error) {
+	if r.Body == nil {
+		return nil, errors.New("request has no body")
+	}
+	defer r.Body.Close()
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxEventPayloadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event: %w", err)
+	}
+	if len(payload) > maxEventPayloadBytes {
+		return nil, errors.New("event payload is too large")
+	}
+	return c.ConstructEvent(payload, opts...)
+}
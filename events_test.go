@@ -0,0 +1,220 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetEventsByID(t *testing.T) {
+	var inFlight, maxInFlight, calls atomic.Int32
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls.Add(1)
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				current := maxInFlight.Load()
+				if n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			id := strings.TrimPrefix(req.URL.Path, "/v2/events/")
+			if strings.HasPrefix(id, "evnt_missing") {
+				return jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`), nil
+			}
+			return jsonResponse(200, fmt.Sprintf(`{"id":%q,"object":"event","type":"customer.created","livemode":false,"pending_webhooks":0,"data":{},"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`, id)), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ids := []string{"evnt_1", "evnt_missing_1", "evnt_2", "evnt_3", "evnt_missing_2", "evnt_1"}
+	results := client.GetEventsByID(context.Background(), ids, 2)
+
+	if len(results) != 5 {
+		t.Errorf("Result count incorrect. Got: %d, Expected: 5", len(results))
+	}
+	if got := calls.Load(); got != 5 {
+		t.Errorf("Request count incorrect. Got: %d, Expected: 5", got)
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("Concurrency exceeded. Got: %d, Expected: at most 2", got)
+	}
+	for _, id := range []string{"evnt_1", "evnt_2", "evnt_3"} {
+		result := results[id]
+		if result.Err != nil || result.Event == nil || result.Event.Id != id {
+			t.Errorf("Result for %s incorrect. Got: %+v", id, result)
+		}
+	}
+	for _, id := range []string{"evnt_missing_1", "evnt_missing_2"} {
+		result := results[id]
+		var apiErr *APIError
+		if !errors.As(result.Err, &apiErr) || !apiErr.IsNotFound() || result.Event != nil {
+			t.Errorf("Expected not found APIError for %s, got: %+v", id, result)
+		}
+	}
+
+	var batchErr *BatchError
+	if err := results.Err(); !errors.As(err, &batchErr) || len(batchErr.Errors) != 2 {
+		t.Fatalf("Expected a BatchError with 2 items, got: %v", err)
+	}
+	if batchErr.Errors[0].ID != "evnt_missing_1" || batchErr.Errors[1].ID != "evnt_missing_2" {
+		t.Errorf("Failed IDs incorrect. Got: %s, %s", batchErr.Errors[0].ID, batchErr.Errors[1].ID)
+	}
+	var apiErr *APIError
+	if !errors.As(results.Err(), &apiErr) || !apiErr.IsNotFound() {
+		t.Errorf("Expected errors.As to reach the not found APIError, got: %v", results.Err())
+	}
+	if err := (EventResults{"evnt_1": {Event: &EventResponse{}}}).Err(); err != nil {
+		t.Errorf("Expected nil when every event was fetched, got: %v", err)
+	}
+}
+
+func TestEventDecodeData(t *testing.T) {
+	type loyaltyPoints struct {
+		CustomerID string `json:"customer_id"`
+		Points     int    `json:"points"`
+	}
+	RegisterEventType("test.loyalty_points.granted", func() any { return new(loyaltyPoints) })
+	t.Cleanup(func() { eventTypes.Delete("test.loyalty_points.granted") })
+	decode := func(t *testing.T, body string) *EventResponse {
+		t.Helper()
+		var event EventResponse
+		if err := json.Unmarshal([]byte(body), &event); err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		return &event
+	}
+
+	t.Run("decodes a registered type", func(t *testing.T) {
+		event := decode(t, `{"id":"evnt_1","type":"test.loyalty_points.granted","data":{"customer_id":"cus_1","points":120}}`)
+		data, err := event.DecodeData()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		points, ok := data.(*loyaltyPoints)
+		if !ok {
+			t.Fatalf("Data type incorrect. Got: %T, Expected: *loyaltyPoints", data)
+		}
+		if points.CustomerID != "cus_1" || points.Points != 120 {
+			t.Errorf("Data incorrect. Got: %+v", points)
+		}
+	})
+
+	t.Run("returns raw JSON for an unregistered type", func(t *testing.T) {
+		event := decode(t, `{"id":"evnt_2","type":"test.unregistered","data":{"id":"x"}}`)
+		data, err := event.DecodeData()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if raw, ok := data.(json.RawMessage); !ok || string(raw) != `{"id":"x"}` {
+			t.Errorf("Data incorrect. Got: %#v", data)
+		}
+	})
+
+	t.Run("reports data that does not fit the registered type", func(t *testing.T) {
+		event := decode(t, `{"id":"evnt_3","type":"test.loyalty_points.granted","data":{"points":"many"}}`)
+		if _, err := event.DecodeData(); err == nil {
+			t.Error("Expected error for mismatched data")
+		}
+	})
+
+	t.Run("panics on a nil constructor", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected a panic")
+			}
+		}()
+		RegisterEventType("test.nil", nil)
+	})
+}
+
+func TestConstructEvent(t *testing.T) {
+	event := func(livemode string) []byte {
+		return []byte(`{"id":"evnt_1","object":"event","type":"customer.created",` + livemode + `"pending_webhooks":1,"data":{"id":"cus_1"},"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`)
+	}
+	newClient := func(t *testing.T, apiKey string) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses(apiKey)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("decodes an event", func(t *testing.T) {
+		got, err := newClient(t, "sk_test_example").ConstructEvent(event(`"livemode":true,`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.Id != "evnt_1" || got.Type != "customer.created" || !got.Livemode {
+			t.Errorf("Event incorrect. Got: %+v", got)
+		}
+	})
+
+	t.Run("accepts events matching the key mode", func(t *testing.T) {
+		for apiKey, livemode := range map[string]string{"sk_test_example": "false", "sk_live_example": "true"} {
+			if _, err := newClient(t, apiKey).ConstructEvent(event(`"livemode":`+livemode+`,`), WithEventModeCheck()); err != nil {
+				t.Errorf("Unexpected error for %s: %v", apiKey, err)
+			}
+		}
+	})
+
+	t.Run("rejects events not matching the key mode", func(t *testing.T) {
+		for apiKey, livemode := range map[string]string{"sk_test_example": "true", "sk_live_example": "false"} {
+			_, err := newClient(t, apiKey).ConstructEvent(event(`"livemode":`+livemode+`,`), WithEventModeCheck())
+			if !errors.Is(err, ErrEventModeMismatch) {
+				t.Errorf("Expected ErrEventModeMismatch for %s, got: %v", apiKey, err)
+			}
+		}
+	})
+
+	t.Run("rejects events without a livemode flag", func(t *testing.T) {
+		if _, err := newClient(t, "sk_test_example").ConstructEvent(event(""), WithEventModeCheck()); err == nil {
+			t.Error("Expected error for missing livemode")
+		}
+	})
+
+	t.Run("rejects invalid payloads", func(t *testing.T) {
+		for _, payload := range []string{`not json`, `{"object":"event"}`} {
+			if _, err := newClient(t, "sk_test_example").ConstructEvent([]byte(payload)); err == nil {
+				t.Errorf("Expected error for %s", payload)
+			}
+		}
+	})
+}
+
+func TestConstructEventFromRequest(t *testing.T) {
+	client, err := NewPayjpClientWithResponses("sk_test_example")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("decodes the request body", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"id":"evnt_1","type":"customer.created","livemode":false,"data":{}}`))
+		event, err := client.ConstructEventFromRequest(req, WithEventModeCheck())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if event.Id != "evnt_1" {
+			t.Errorf("Id incorrect. Got: %s, Expected: evnt_1", event.Id)
+		}
+	})
+
+	t.Run("rejects oversized bodies", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"id":"evnt_1","type":"customer.created","data":{"pad":"`+strings.Repeat("x", 1<<20)+`"}}`))
+		if _, err := client.ConstructEventFromRequest(req); err == nil {
+			t.Error("Expected error for an oversized body")
+		}
+	})
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"go/format"
 	"net/http"
 	"os"
 	"regexp"
@@ -10,11 +15,16 @@ import (
 	"strings"
 )
 
-// Field name mappings for success response structs
-// Error field mappings are generated dynamically from client.gen.go
-var fieldMappings = map[string]string{
-	"JSON200": "Result",
-	"JSON201": "Result",
+// extractSuccessFieldMappings dynamically extracts success field mappings from the generated code.
+// It finds all JSON2XX fields (JSON200, JSON201, JSON202, ...) and maps them to Result,
+// so a response's success body is found in Result whichever 2xx status the operation returns.
+func extractSuccessFieldMappings(content string) map[string]string {
+	pattern := regexp.MustCompile(`\bJSON2\d\d\b`)
+	mappings := make(map[string]string)
+	for _, match := range pattern.FindAllString(content, -1) {
+		mappings[match] = "Result"
+	}
+	return mappings
 }
 
 // ErrorMapping represents a mapping from error field name to HTTP status code
@@ -48,6 +58,12 @@ func extractErrorFieldMappings(content string) map[string]string {
 func main() {
 	inputFile := "client.gen.go"
 	outputMappingsFile := "error_mappings.gen.go"
+	outputAmountsFile := "amounts.gen.go"
+	outputOperationIDsFile := "operation_ids.gen.go"
+	outputListFile := "list.gen.go"
+	outputZeroFile := "zero.gen.go"
+	outputResponseFile := "response.gen.go"
+	outputMoneyFile := "money.gen.go"
 
 	// Read the generated file
 	data, err := os.ReadFile(inputFile)
@@ -58,13 +74,14 @@ func main() {
 
 	content := string(data)
 
-	// Dynamically extract error field mappings from the generated code
+	// Dynamically extract success and error field mappings from the generated code
+	successFieldMappings := extractSuccessFieldMappings(content)
 	errorFieldMappings := extractErrorFieldMappings(content)
 
 	modified := content
 
 	// Apply success response field name mappings
-	for old, new := range fieldMappings {
+	for old, new := range successFieldMappings {
 		modified = replaceFieldName(modified, old, new)
 	}
 
@@ -76,6 +93,9 @@ func main() {
 	// Apply dynamic ID parameter mappings (xxxId -> xxxID)
 	modified = replaceIDParams(modified)
 
+	// Keep the SDK's transport options when WithHTTPClient is applied after them
+	modified = replaceWithHTTPClient(modified)
+
 	// Write the modified file
 	if err := os.WriteFile(inputFile, []byte(modified), 0644); err != nil {
 		fmt.Printf("Error writing file: %v\n", err)
@@ -89,9 +109,56 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Generate amounts.gen.go
+	if err := generateAmountsFile(outputAmountsFile, extractAmountFields(modified)); err != nil {
+		fmt.Printf("Error generating %s: %v\n", outputAmountsFile, err)
+		os.Exit(1)
+	}
+
+	// Generate operation_ids.gen.go
+	operationIDs, err := extractOperationIDs(modified)
+	if err != nil {
+		fmt.Printf("Error extracting operation ids: %v\n", err)
+		os.Exit(1)
+	}
+	if err := generateOperationIDsFile(outputOperationIDsFile, operationIDs); err != nil {
+		fmt.Printf("Error generating %s: %v\n", outputOperationIDsFile, err)
+		os.Exit(1)
+	}
+
+	// Generate list.gen.go
+	if err := generateListFile(outputListFile, extractListResponses(modified)); err != nil {
+		fmt.Printf("Error generating %s: %v\n", outputListFile, err)
+		os.Exit(1)
+	}
+
+	// Generate zero.gen.go
+	if err := generateZeroFile(outputZeroFile, extractZeroStructs(modified)); err != nil {
+		fmt.Printf("Error generating %s: %v\n", outputZeroFile, err)
+		os.Exit(1)
+	}
+
+	// Generate response.gen.go
+	if err := generateResponseFile(outputResponseFile, extractResponseTypes(modified)); err != nil {
+		fmt.Printf("Error generating %s: %v\n", outputResponseFile, err)
+		os.Exit(1)
+	}
+
+	// Generate money.gen.go
+	if err := generateMoneyFile(outputMoneyFile, extractMoneyStructs(modified)); err != nil {
+		fmt.Printf("Error generating %s: %v\n", outputMoneyFile, err)
+		os.Exit(1)
+	}
+
 	fmt.Println("Successfully post-processed client.gen.go")
 	fmt.Printf("Successfully generated %s\n", outputMappingsFile)
-	printSummary(content, modified, errorFieldMappings)
+	fmt.Printf("Successfully generated %s\n", outputAmountsFile)
+	fmt.Printf("Successfully generated %s\n", outputOperationIDsFile)
+	fmt.Printf("Successfully generated %s\n", outputListFile)
+	fmt.Printf("Successfully generated %s\n", outputZeroFile)
+	fmt.Printf("Successfully generated %s\n", outputResponseFile)
+	fmt.Printf("Successfully generated %s\n", outputMoneyFile)
+	printSummary(content, modified, successFieldMappings, errorFieldMappings)
 }
 
 // replaceFieldName replaces struct field names and their references
@@ -112,19 +179,35 @@ func replaceFieldName(content, oldName, newName string) string {
 	return content
 }
 
+// idParamPattern matches an unexported identifier ending with "Id"
+var idParamPattern = regexp.MustCompile(`\b([a-z][a-zA-Z0-9]*)Id\b`)
+
 // replaceIDParams dynamically replaces ID parameter names to follow Go naming conventions.
 // It converts camelCase "xxxId" patterns to "xxxID" (e.g., customerId -> customerID).
 // This automatically handles any ID parameters from the OpenAPI spec without manual mapping.
+//
+// Only the final "Id" of a whole identifier starting with a lowercase letter is converted, so
+// uppercase runs and digits before it are kept as they are (httpId -> httpID,
+// threeDSecureId -> threeDSecureID, oauth2Id -> oauth2ID). These intentionally don't convert:
+//   - exported identifiers (CustomerId, URLId), whose names are part of the public API
+//   - "Id" not at the end of the identifier (customerIds, someIdentifier)
+//   - a bare "id" or "Id"
 func replaceIDParams(content string) string {
-	// Pattern: lowercase letter followed by camelCase ending with "Id"
-	// Examples: customerId, paymentFlowId, checkoutSessionId
-	// This won't match: Invalid (starts with uppercase), id (no prefix)
-	pattern := regexp.MustCompile(`\b([a-z][a-zA-Z]*)Id\b`)
-	return pattern.ReplaceAllString(content, "${1}ID")
+	return idParamPattern.ReplaceAllString(content, "${1}ID")
+}
+
+// withHTTPClientPattern matches the statement of the generated WithHTTPClient replacing the doer
+var withHTTPClientPattern = regexp.MustCompile(`(func WithHTTPClient\(doer HttpRequestDoer\) ClientOption \{\n\treturn func\(c \*Client\) error \{\n\t\t)c\.Client = doer\n`)
+
+// replaceWithHTTPClient makes the generated WithHTTPClient set the doer with setHTTPClient, which
+// keeps the SDK's transport options (see transport.go) whatever the order of the options.
+// Content that has already been processed is left unchanged.
+func replaceWithHTTPClient(content string) string {
+	return withHTTPClientPattern.ReplaceAllString(content, "${1}setHTTPClient(c, doer)\n")
 }
 
 // printSummary prints a summary of changes made
-func printSummary(original, modified string, errorFieldMappings map[string]string) {
+func printSummary(original, modified string, successFieldMappings, errorFieldMappings map[string]string) {
 	if original == modified {
 		fmt.Println("No changes were made.")
 		return
@@ -133,7 +216,7 @@ func printSummary(original, modified string, errorFieldMappings map[string]strin
 	fmt.Println("\nChanges applied:")
 
 	// Print success response mappings
-	for old, new := range fieldMappings {
+	for old, new := range successFieldMappings {
 		oldCount := strings.Count(original, old)
 		if oldCount > 0 {
 			fmt.Printf("  - %s → %s: %d replacements\n", old, new, oldCount)
@@ -219,3 +302,465 @@ func httpStatusName(code int) string {
 	// "Bad Request" -> "BadRequest"
 	return strings.ReplaceAll(text, " ", "")
 }
+
+// AmountStruct represents a response struct that needs a generated UnmarshalJSON
+type AmountStruct struct {
+	TypeName string
+	// JSONFields are the JSON names of integer amount fields, which also accept numeric strings
+	JSONFields []string
+}
+
+var (
+	structPattern      = regexp.MustCompile(`(?ms)^type (\w+) struct \{\n(.*?)^\}`)
+	amountFieldPattern = regexp.MustCompile("(?m)^\\s*\\w+\\s+\\*?int\\s+`json:\"(amount[a-z_]*)[,\"]")
+	freeFormPattern    = regexp.MustCompile(`interface\{\}`)
+)
+
+// extractAmountFields finds response structs with integer amount fields (json tags starting with "amount")
+// or free-form interface{} fields, which must decode numbers as json.Number to keep big integers exact.
+// Returns a slice sorted by type name for consistent output
+func extractAmountFields(content string) []AmountStruct {
+	var structs []AmountStruct
+	for _, match := range structPattern.FindAllStringSubmatch(content, -1) {
+		typeName, body := match[1], match[2]
+		if !strings.HasSuffix(typeName, "Response") {
+			continue
+		}
+		var fields []string
+		for _, field := range amountFieldPattern.FindAllStringSubmatch(body, -1) {
+			fields = append(fields, field[1])
+		}
+		if len(fields) > 0 || freeFormPattern.MatchString(body) {
+			structs = append(structs, AmountStruct{TypeName: typeName, JSONFields: fields})
+		}
+	}
+	sort.Slice(structs, func(i, j int) bool {
+		return structs[i].TypeName < structs[j].TypeName
+	})
+	return structs
+}
+
+// generateAmountsFile generates the amounts.gen.go file.
+// Each struct gets an UnmarshalJSON that accepts its amount fields as JSON numbers or numeric strings
+// and decodes free-form values with json.Number.
+func generateAmountsFile(filename string, structs []AmountStruct) error {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by postprocess. DO NOT EDIT.\n\n")
+	sb.WriteString("package payjpv2\n")
+	for _, s := range structs {
+		sb.WriteString(fmt.Sprintf("\n// UnmarshalJSON decodes %s, keeping free-form numbers as json.Number", s.TypeName))
+		if len(s.JSONFields) > 0 {
+			sb.WriteString(" and accepting amount fields encoded as numeric strings")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("func (r *%s) UnmarshalJSON(b []byte) error {\n", s.TypeName))
+		if len(s.JSONFields) > 0 {
+			quoted := make([]string, len(s.JSONFields))
+			for i, f := range s.JSONFields {
+				quoted[i] = fmt.Sprintf("%q", f)
+			}
+			sb.WriteString(fmt.Sprintf("\tb, err := normalizeAmountFields(b, %s)\n", strings.Join(quoted, ", ")))
+			sb.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		}
+		sb.WriteString(fmt.Sprintf("\ttype plain %s\n", s.TypeName))
+		sb.WriteString("\treturn decodeJSON(b, (*plain)(r))\n")
+		sb.WriteString("}\n")
+	}
+
+	return os.WriteFile(filename, []byte(sb.String()), 0644)
+}
+
+var (
+	swaggerSpecPattern     = regexp.MustCompile(`(?s)var swaggerSpec = \[\]string\{\n(.*?)\n\}`)
+	interfacePattern       = regexp.MustCompile(`(?ms)^type (?:ClientInterface|ClientWithResponsesInterface) interface \{\n(.*?)^\}`)
+	interfaceMethodPattern = regexp.MustCompile(`(?m)^\t(\w+)\(ctx context\.Context`)
+)
+
+// extractEmbeddedSpec decodes the base64 encoded, gzipped OpenAPI spec embedded in the generated code
+func extractEmbeddedSpec(content string) ([]byte, error) {
+	match := swaggerSpecPattern.FindStringSubmatch(content)
+	if match == nil {
+		return nil, fmt.Errorf("embedded spec not found")
+	}
+	var encoded strings.Builder
+	for _, line := range strings.Split(match[1], "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if line == "" {
+			continue
+		}
+		part, err := strconv.Unquote(line)
+		if err != nil {
+			return nil, fmt.Errorf("error unquoting spec: %w", err)
+		}
+		encoded.WriteString(part)
+	}
+	zipped, err := base64.StdEncoding.DecodeString(encoded.String())
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(zr); err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// operationGoName converts an operationId to the name oapi-codegen gives its client method
+// (e.g., "get_customer" -> "GetCustomer")
+func operationGoName(operationID string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range operationID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// extractOperationIDs maps every generated client method (including the WithBody and
+// WithResponse variants) to the operationId of the OpenAPI operation it calls
+func extractOperationIDs(content string) (map[string]string, error) {
+	specData, err := extractEmbeddedSpec(content)
+	if err != nil {
+		return nil, err
+	}
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(specData, &spec); err != nil {
+		return nil, fmt.Errorf("error parsing spec: %w", err)
+	}
+	byGoName := make(map[string]string)
+	for _, pathItem := range spec.Paths {
+		for _, raw := range pathItem {
+			// Path items also hold non-operation fields such as parameters
+			var op struct {
+				OperationID string `json:"operationId"`
+			}
+			if json.Unmarshal(raw, &op) == nil && op.OperationID != "" {
+				byGoName[operationGoName(op.OperationID)] = op.OperationID
+			}
+		}
+	}
+
+	operationIDs := make(map[string]string)
+	for _, iface := range interfacePattern.FindAllStringSubmatch(content, -1) {
+		for _, method := range interfaceMethodPattern.FindAllStringSubmatch(iface[1], -1) {
+			name := strings.TrimSuffix(strings.TrimSuffix(method[1], "WithResponse"), "WithBody")
+			if operationID, ok := byGoName[name]; ok {
+				operationIDs[method[1]] = operationID
+			}
+		}
+	}
+	return operationIDs, nil
+}
+
+// generateOperationIDsFile generates the operation_ids.gen.go file
+func generateOperationIDsFile(filename string, operationIDs map[string]string) error {
+	methods := make([]string, 0, len(operationIDs))
+	for method := range operationIDs {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by postprocess. DO NOT EDIT.\n\n")
+	sb.WriteString("package payjpv2\n\n")
+	sb.WriteString("// OperationIDs maps each generated client method to the operationId of the OpenAPI operation it calls,\n")
+	sb.WriteString("// e.g. for logging, metrics or looking the operation up in the API reference\n")
+	sb.WriteString("var OperationIDs = map[string]string{\n")
+	for _, method := range methods {
+		sb.WriteString(fmt.Sprintf("\t%q: %q,\n", method, operationIDs[method]))
+	}
+	sb.WriteString("}\n")
+
+	// gofmt aligns the map values
+	formatted, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, formatted, 0644)
+}
+
+// ListResponse represents a generated response struct whose Result is a list envelope
+type ListResponse struct {
+	TypeName string
+	ItemType string
+	// EnvelopeType is the type of the response's Result, e.g. CustomerListResponse
+	EnvelopeType string
+	// Fields are the standard list fields (has_more, url, object) present on the envelope
+	Fields []ListField
+}
+
+// ListField is a standard list envelope field copied into ListResult
+type ListField struct {
+	Name     string
+	JSONName string
+	Pointer  bool
+}
+
+var (
+	listResultPattern = regexp.MustCompile(`(?m)^\s*Result\s+\*(\w+ListResponse)$`)
+	listDataPattern   = regexp.MustCompile("(?m)^\\s*Data\\s+\\[\\](\\w+)\\s+`json:\"data\"`")
+	listFieldPattern  = regexp.MustCompile("(?m)^\\s*(\\w+)\\s+(\\*?)(?:bool|string)\\s+`json:\"(has_more|url|object)[,\"]")
+)
+
+// listResultFields maps the standard list fields to the ListResult fields they are decoded into
+var listResultFields = map[string]string{
+	"has_more": "hasMore",
+	"url":      "url",
+	"object":   "object",
+}
+
+// extractListResponses finds the response structs whose Result is a list envelope ({data, has_more, ...})
+// and resolves the item type and standard fields of the envelope.
+// Returns a slice sorted by type name for consistent output
+func extractListResponses(content string) []ListResponse {
+	envelopes := make(map[string]ListResponse)
+	var candidates []ListResponse
+	for _, match := range structPattern.FindAllStringSubmatch(content, -1) {
+		typeName, body := match[1], match[2]
+		if data := listDataPattern.FindStringSubmatch(body); data != nil {
+			envelope := ListResponse{ItemType: data[1]}
+			for _, field := range listFieldPattern.FindAllStringSubmatch(body, -1) {
+				envelope.Fields = append(envelope.Fields, ListField{Name: field[1], JSONName: field[3], Pointer: field[2] == "*"})
+			}
+			envelopes[typeName] = envelope
+		}
+		if result := listResultPattern.FindStringSubmatch(body); result != nil {
+			candidates = append(candidates, ListResponse{TypeName: typeName, ItemType: result[1]})
+		}
+	}
+
+	var responses []ListResponse
+	for _, c := range candidates {
+		envelope, ok := envelopes[c.ItemType]
+		if !ok {
+			continue
+		}
+		responses = append(responses, ListResponse{TypeName: c.TypeName, ItemType: envelope.ItemType, EnvelopeType: c.ItemType, Fields: envelope.Fields})
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].TypeName < responses[j].TypeName
+	})
+	return responses
+}
+
+// generateListFile generates the list.gen.go file.
+// Each list response gets a Total accessor and the listResult method used by ExtractList,
+// and each list envelope an UnmarshalJSON that also accepts a bare array of items.
+func generateListFile(filename string, responses []ListResponse) error {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by postprocess. DO NOT EDIT.\n\n")
+	sb.WriteString("package payjpv2\n")
+	envelopes := make(map[string]bool)
+	for _, r := range responses {
+		if r.EnvelopeType != "" && !envelopes[r.EnvelopeType] {
+			envelopes[r.EnvelopeType] = true
+			sb.WriteString(fmt.Sprintf("\n// UnmarshalJSON decodes %s, accepting a bare array of items as a single page\n", r.EnvelopeType))
+			sb.WriteString(fmt.Sprintf("func (r *%s) UnmarshalJSON(b []byte) error {\n", r.EnvelopeType))
+			sb.WriteString(fmt.Sprintf("\ttype plain %s\n", r.EnvelopeType))
+			sb.WriteString("\treturn decodeListEnvelope(b, (*plain)(r), &r.Data)\n")
+			sb.WriteString("}\n")
+		}
+
+		sb.WriteString("\n// Total returns the total number of items reported by the list response, if the endpoint includes one\n")
+		sb.WriteString(fmt.Sprintf("func (r *%s) Total() (int, bool) {\n", r.TypeName))
+		sb.WriteString("\tif r == nil {\n\t\treturn 0, false\n\t}\n")
+		sb.WriteString("\treturn listTotal(r.Body)\n")
+		sb.WriteString("}\n\n")
+		sb.WriteString(fmt.Sprintf("func (r *%s) listResult() *ListResult[%s] {\n", r.TypeName, r.ItemType))
+		sb.WriteString("\tif r == nil || r.Result == nil {\n\t\treturn nil\n\t}\n")
+		sb.WriteString("\tl := newListResult(r.Result.Data, r.Body)\n")
+		for _, f := range r.Fields {
+			target := listResultFields[f.JSONName]
+			if f.Pointer {
+				sb.WriteString(fmt.Sprintf("\tif r.Result.%s != nil {\n\t\tl.%s = *r.Result.%s\n\t}\n", f.Name, target, f.Name))
+			} else {
+				sb.WriteString(fmt.Sprintf("\tl.%s = r.Result.%s\n", target, f.Name))
+			}
+		}
+		sb.WriteString("\treturn l\n")
+		sb.WriteString("}\n")
+	}
+
+	return os.WriteFile(filename, []byte(sb.String()), 0644)
+}
+
+// ZeroStruct represents a request struct that is nested in other requests and gets a generated IsZero
+type ZeroStruct struct {
+	TypeName string
+	Fields   []ZeroField
+}
+
+// ZeroField is a field of a ZeroStruct
+type ZeroField struct {
+	Name string
+	Type string
+}
+
+var (
+	nestedRequestPattern = regexp.MustCompile("(?m)^\\s*\\w+\\s+\\*?(\\w+Request)\\s+`json:")
+	structFieldPattern   = regexp.MustCompile("(?m)^\\s*(\\w+)\\s+(\\S+)\\s+`json:")
+)
+
+// extractZeroStructs finds the request structs used as fields of other requests.
+// Returns a slice sorted by type name for consistent output
+func extractZeroStructs(content string) []ZeroStruct {
+	bodies := make(map[string]string)
+	nested := make(map[string]bool)
+	for _, match := range structPattern.FindAllStringSubmatch(content, -1) {
+		bodies[match[1]] = match[2]
+		for _, field := range nestedRequestPattern.FindAllStringSubmatch(match[2], -1) {
+			nested[field[1]] = true
+		}
+	}
+
+	var structs []ZeroStruct
+	for typeName := range nested {
+		body, ok := bodies[typeName]
+		if !ok {
+			continue
+		}
+		s := ZeroStruct{TypeName: typeName}
+		for _, field := range structFieldPattern.FindAllStringSubmatch(body, -1) {
+			s.Fields = append(s.Fields, ZeroField{Name: field[1], Type: field[2]})
+		}
+		structs = append(structs, s)
+	}
+	sort.Slice(structs, func(i, j int) bool {
+		return structs[i].TypeName < structs[j].TypeName
+	})
+	return structs
+}
+
+// zeroCheck returns the expression reporting whether field f of r holds its zero value
+func zeroCheck(f ZeroField, nested map[string]bool) string {
+	switch {
+	case strings.HasPrefix(f.Type, "*"), strings.HasPrefix(f.Type, "[]"), strings.HasPrefix(f.Type, "map["):
+		return fmt.Sprintf("r.%s == nil", f.Name)
+	case f.Type == "string":
+		return fmt.Sprintf("r.%s == \"\"", f.Name)
+	case f.Type == "bool":
+		return fmt.Sprintf("!r.%s", f.Name)
+	case f.Type == "int" || f.Type == "float32" || f.Type == "float64":
+		return fmt.Sprintf("r.%s == 0", f.Name)
+	case nested[f.Type]:
+		return fmt.Sprintf("r.%s.IsZero()", f.Name)
+	default:
+		return fmt.Sprintf("isZeroValue(r.%s)", f.Name)
+	}
+}
+
+// generateZeroFile generates the zero.gen.go file.
+// Each struct gets an IsZero method, used by Validate to report empty nested objects.
+func generateZeroFile(filename string, structs []ZeroStruct) error {
+	nested := make(map[string]bool)
+	for _, s := range structs {
+		nested[s.TypeName] = true
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by postprocess. DO NOT EDIT.\n\n")
+	sb.WriteString("package payjpv2\n")
+	for _, s := range structs {
+		checks := make([]string, len(s.Fields))
+		for i, f := range s.Fields {
+			checks[i] = zeroCheck(f, nested)
+		}
+		expr := "true"
+		if len(checks) > 0 {
+			expr = strings.Join(checks, " &&\n\t\t")
+		}
+		sb.WriteString(fmt.Sprintf("\n// IsZero reports whether no field of the %s is set\n", s.TypeName))
+		sb.WriteString(fmt.Sprintf("func (r %s) IsZero() bool {\n", s.TypeName))
+		sb.WriteString(fmt.Sprintf("\treturn %s\n", expr))
+		sb.WriteString("}\n")
+	}
+
+	return os.WriteFile(filename, []byte(sb.String()), 0644)
+}
+
+var statusCodeMethodPattern = regexp.MustCompile(`(?m)^func \(r (\w+)\) StatusCode\(\) int \{`)
+
+// extractResponseTypes finds the generated response types, which all have Status and StatusCode methods.
+// Returns a sorted slice for consistent output
+func extractResponseTypes(content string) []string {
+	var types []string
+	for _, match := range statusCodeMethodPattern.FindAllStringSubmatch(content, -1) {
+		types = append(types, match[1])
+	}
+	sort.Strings(types)
+	return types
+}
+
+// generateResponseFile generates the response.gen.go file with the Response interface
+// and compile-time assertions that every generated response type implements it
+func generateResponseFile(filename string, types []string) error {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by postprocess. DO NOT EDIT.\n\n")
+	sb.WriteString("package payjpv2\n\n")
+	sb.WriteString("// Response is implemented by every generated XxxResponse type, reading the status from its HTTPResponse\n")
+	sb.WriteString("type Response interface {\n")
+	sb.WriteString("\t// Status returns HTTPResponse.Status\n")
+	sb.WriteString("\tStatus() string\n")
+	sb.WriteString("\t// StatusCode returns HTTPResponse.StatusCode\n")
+	sb.WriteString("\tStatusCode() int\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("var (\n")
+	for _, t := range types {
+		sb.WriteString(fmt.Sprintf("\t_ Response = (*%s)(nil)\n", t))
+	}
+	sb.WriteString(")\n")
+
+	return os.WriteFile(filename, []byte(sb.String()), 0644)
+}
+
+var (
+	moneyAmountPattern   = regexp.MustCompile("(?m)^\\s*Amount\\s+int\\s+`json:\"amount\"`")
+	moneyCurrencyPattern = regexp.MustCompile("(?m)^\\s*Currency\\s+Currency\\s+`json:\"currency\"`")
+)
+
+// extractMoneyStructs finds response structs with a required amount and currency pair.
+// Returns a sorted slice for consistent output
+func extractMoneyStructs(content string) []string {
+	var types []string
+	for _, match := range structPattern.FindAllStringSubmatch(content, -1) {
+		typeName, body := match[1], match[2]
+		if !strings.HasSuffix(typeName, "Response") {
+			continue
+		}
+		if moneyAmountPattern.MatchString(body) && moneyCurrencyPattern.MatchString(body) {
+			types = append(types, typeName)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// generateMoneyFile generates the money.gen.go file with a Money accessor for each struct
+// returning its amount and currency as a single value
+func generateMoneyFile(filename string, types []string) error {
+	var sb strings.Builder
+	sb.WriteString("// Code generated by postprocess. DO NOT EDIT.\n\n")
+	sb.WriteString("package payjpv2\n")
+	for _, t := range types {
+		sb.WriteString(fmt.Sprintf("\n// Money returns the amount and currency of the %s\n", t))
+		sb.WriteString(fmt.Sprintf("func (r %s) Money() Money {\n", t))
+		sb.WriteString("\treturn Money{Amount: r.Amount, Currency: r.Currency}\n")
+		sb.WriteString("}\n")
+	}
+
+	return os.WriteFile(filename, []byte(sb.String()), 0644)
+}
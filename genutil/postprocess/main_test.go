@@ -1,6 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -34,6 +38,22 @@ func TestReplaceIDParams(t *testing.T) {
 		{"no_match_provide", "provide", "provide"},
 		{"no_match_inside_word", "providerId", "providerID"}, // This SHOULD match as it ends with Id
 
+		// Acronyms and digits before Id
+		{"acronym_threeDSecureId", "threeDSecureId", "threeDSecureID"},
+		{"acronym_lowercase_httpId", "httpId", "httpID"},
+		{"acronym_inner_run", "paymentURLId", "paymentURLID"},
+		{"digits_oauth2Id", "oauth2Id", "oauth2ID"},
+		{"digits_only_prefix", "v2Id", "v2ID"},
+		{"longer_token_subtenantId", "subtenantId", "subtenantID"},
+		{"longer_token_tenantId_inside", "parentSubtenantId tenantId", "parentSubtenantID tenantID"},
+
+		// Intentionally not converted
+		{"no_match_exported_acronym", "URLId", "URLId"},
+		{"no_match_exported_field", "CustomerId", "CustomerId"},
+		{"no_match_plural", "customerIds", "customerIds"},
+		{"no_match_bare_Id", "Id", "Id"},
+		{"no_match_digit_start", "2Id", "2Id"},
+
 		// Word boundary tests
 		{"boundary_parenthesis", "GetCustomer(customerId)", "GetCustomer(customerID)"},
 		{"boundary_comma", "customerId, paymentId", "customerID, paymentID"},
@@ -105,6 +125,55 @@ func TestReplaceFieldName(t *testing.T) {
 	}
 }
 
+func TestExtractSuccessFieldMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected map[string]string
+	}{
+		{
+			name:     "no_success_fields",
+			content:  "type Response struct { Data string }",
+			expected: map[string]string{},
+		},
+		{
+			name:    "json200_and_json201",
+			content: "JSON200 *CustomerResponse\nJSON201 *CustomerResponse",
+			expected: map[string]string{
+				"JSON200": "Result",
+				"JSON201": "Result",
+			},
+		},
+		{
+			name:    "json202",
+			content: "JSON202 *ExportResponse\nif resp.JSON202 != nil {}",
+			expected: map[string]string{
+				"JSON202": "Result",
+			},
+		},
+		{
+			name:     "ignores_non_2xx_and_partial_names",
+			content:  "JSON400 *ErrorResponse\nJSON2001 string\nApplicationproblemJSON404 *ErrorResponse",
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := extractSuccessFieldMappings(tt.content)
+			if len(result) != len(tt.expected) {
+				t.Errorf("extractSuccessFieldMappings() returned %d mappings, want %d", len(result), len(tt.expected))
+				return
+			}
+			for k, v := range tt.expected {
+				if result[k] != v {
+					t.Errorf("extractSuccessFieldMappings()[%q] = %q, want %q", k, result[k], v)
+				}
+			}
+		})
+	}
+}
+
 func TestExtractErrorFieldMappings(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -254,3 +323,463 @@ func TestHttpStatusName(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractAmountFields(t *testing.T) {
+	content := "type PaymentFlowResponse struct {\n" +
+		"\t// Amount 支払い予定の金額\n" +
+		"\tAmount int `json:\"amount\"`\n\n" +
+		"\tAmountReceived *int `json:\"amount_received\"`\n" +
+		"\tCurrency  Currency  `json:\"currency\"`\n" +
+		"}\n" +
+		"type PaymentFlowCreateRequest struct {\n" +
+		"\tAmount        int            `json:\"amount\"`\n" +
+		"}\n" +
+		"type CheckoutSessionLineItemDataResponse struct {\n" +
+		"\tAmountTotal int      `json:\"amount_total\"`\n" +
+		"\tQuantity    int      `json:\"quantity\"`\n" +
+		"}\n" +
+		"type EventResponse struct {\n" +
+		"\tData map[string]interface{} `json:\"data\"`\n" +
+		"}\n" +
+		"type CustomerResponse struct {\n" +
+		"\tEmail *string `json:\"email\"`\n" +
+		"}\n"
+
+	structs := extractAmountFields(content)
+
+	if len(structs) != 3 {
+		t.Fatalf("extractAmountFields() returned %d structs, want 3: %+v", len(structs), structs)
+	}
+	if structs[0].TypeName != "CheckoutSessionLineItemDataResponse" || strings.Join(structs[0].JSONFields, ",") != "amount_total" {
+		t.Errorf("structs[0] = %+v", structs[0])
+	}
+	if structs[1].TypeName != "EventResponse" || len(structs[1].JSONFields) != 0 {
+		t.Errorf("structs[1] = %+v", structs[1])
+	}
+	if structs[2].TypeName != "PaymentFlowResponse" || strings.Join(structs[2].JSONFields, ",") != "amount,amount_received" {
+		t.Errorf("structs[2] = %+v", structs[2])
+	}
+}
+
+func TestGenerateAmountsFile(t *testing.T) {
+	tmpFile := "test_amounts.gen.go"
+	defer os.Remove(tmpFile)
+
+	structs := []AmountStruct{
+		{TypeName: "EventResponse"},
+		{TypeName: "PaymentFlowResponse", JSONFields: []string{"amount", "amount_received"}},
+	}
+
+	if err := generateAmountsFile(tmpFile, structs); err != nil {
+		t.Fatalf("generateAmountsFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	expected := []string{
+		"// Code generated by postprocess. DO NOT EDIT.",
+		"package payjpv2",
+		"func (r *EventResponse) UnmarshalJSON(b []byte) error {\n\ttype plain EventResponse\n",
+		"func (r *PaymentFlowResponse) UnmarshalJSON(b []byte) error {",
+		`b, err := normalizeAmountFields(b, "amount", "amount_received")`,
+		"type plain PaymentFlowResponse",
+		"return decodeJSON(b, (*plain)(r))",
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("generated file missing expected content: %q", exp)
+		}
+	}
+}
+
+// embedSpec returns generated-code content embedding spec the way oapi-codegen does
+func embedSpec(t *testing.T, spec string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(spec)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var sb strings.Builder
+	sb.WriteString("var swaggerSpec = []string{\n\n")
+	for len(encoded) > 0 {
+		n := min(20, len(encoded))
+		sb.WriteString(fmt.Sprintf("\t%q,\n", encoded[:n]))
+		encoded = encoded[n:]
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func TestOperationGoName(t *testing.T) {
+	tests := map[string]string{
+		"GetAllCustomers": "GetAllCustomers",
+		"get_customer":    "GetCustomer",
+		"create-refund":   "CreateRefund",
+	}
+	for input, expected := range tests {
+		if got := operationGoName(input); got != expected {
+			t.Errorf("operationGoName(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestExtractOperationIDs(t *testing.T) {
+	spec := `{"paths":{
+		"/v2/customers":{"get":{"operationId":"GetAllCustomers"},"post":{"operationId":"create_customer"}},
+		"/v2/customers/{customer_id}":{"parameters":[],"get":{"operationId":"GetCustomer"}}
+	}}`
+	content := "type ClientInterface interface {\n" +
+		"\t// GetAllCustomers request\n" +
+		"\tGetAllCustomers(ctx context.Context, params *GetAllCustomersParams, reqEditors ...RequestEditorFn) (*http.Response, error)\n\n" +
+		"\t// CreateCustomerWithBody request with any body\n" +
+		"\tCreateCustomerWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)\n\n" +
+		"\tCreateCustomer(ctx context.Context, body CreateCustomerJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)\n" +
+		"}\n" +
+		"type ClientWithResponsesInterface interface {\n" +
+		"\tGetCustomerWithResponse(ctx context.Context, customerID string, reqEditors ...RequestEditorFn) (*GetCustomerResponse, error)\n" +
+		"}\n" +
+		embedSpec(t, spec)
+
+	operationIDs, err := extractOperationIDs(content)
+	if err != nil {
+		t.Fatalf("extractOperationIDs() error = %v", err)
+	}
+
+	expected := map[string]string{
+		"GetAllCustomers":         "GetAllCustomers",
+		"CreateCustomerWithBody":  "create_customer",
+		"CreateCustomer":          "create_customer",
+		"GetCustomerWithResponse": "GetCustomer",
+	}
+	if len(operationIDs) != len(expected) {
+		t.Errorf("extractOperationIDs() returned %d entries, want %d: %v", len(operationIDs), len(expected), operationIDs)
+	}
+	for method, operationID := range expected {
+		if got := operationIDs[method]; got != operationID {
+			t.Errorf("OperationIDs[%q] = %q, want %q", method, got, operationID)
+		}
+	}
+
+	if _, err := extractOperationIDs("type ClientInterface interface {\n}\n"); err == nil {
+		t.Error("extractOperationIDs() expected error without embedded spec")
+	}
+}
+
+func TestGenerateOperationIDsFile(t *testing.T) {
+	tmpFile := "test_operation_ids.gen.go"
+	defer os.Remove(tmpFile)
+
+	operationIDs := map[string]string{
+		"GetCustomerWithResponse": "GetCustomer",
+		"GetCustomer":             "GetCustomer",
+	}
+
+	if err := generateOperationIDsFile(tmpFile, operationIDs); err != nil {
+		t.Fatalf("generateOperationIDsFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	expected := []string{
+		"// Code generated by postprocess. DO NOT EDIT.",
+		"package payjpv2",
+		"var OperationIDs = map[string]string{\n" +
+			"\t\"GetCustomer\":             \"GetCustomer\",\n" +
+			"\t\"GetCustomerWithResponse\": \"GetCustomer\",\n}",
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("generated file missing expected content: %q\n%s", exp, content)
+		}
+	}
+}
+
+func TestExtractListResponses(t *testing.T) {
+	content := "type CustomerListResponse struct {\n" +
+		"\tData []CustomerResponse `json:\"data\"`\n\n" +
+		"\t// HasMore 次のページがあるかどうか\n" +
+		"\tHasMore bool    `json:\"has_more\"`\n" +
+		"\tObject  *string `json:\"object,omitempty\"`\n\n" +
+		"\t// Url リスト取得URL\n" +
+		"\tUrl string `json:\"url\"`\n" +
+		"}\n" +
+		"type GetAllCustomersResponse struct {\n" +
+		"\tBody                      []byte\n" +
+		"\tHTTPResponse              *http.Response\n" +
+		"\tResult                   *CustomerListResponse\n" +
+		"\tBadRequest *ErrorResponse\n" +
+		"}\n" +
+		"type GetCustomerResponse struct {\n" +
+		"\tBody                      []byte\n" +
+		"\tResult                   *CustomerResponse\n" +
+		"}\n"
+
+	responses := extractListResponses(content)
+
+	if len(responses) != 1 {
+		t.Fatalf("extractListResponses() returned %d responses, want 1: %+v", len(responses), responses)
+	}
+	if responses[0].TypeName != "GetAllCustomersResponse" || responses[0].ItemType != "CustomerResponse" || responses[0].EnvelopeType != "CustomerListResponse" {
+		t.Errorf("responses[0] = %+v", responses[0])
+	}
+	expectedFields := []ListField{
+		{Name: "HasMore", JSONName: "has_more"},
+		{Name: "Object", JSONName: "object", Pointer: true},
+		{Name: "Url", JSONName: "url"},
+	}
+	if len(responses[0].Fields) != len(expectedFields) {
+		t.Fatalf("Fields = %+v, want %+v", responses[0].Fields, expectedFields)
+	}
+	for i, f := range expectedFields {
+		if responses[0].Fields[i] != f {
+			t.Errorf("Fields[%d] = %+v, want %+v", i, responses[0].Fields[i], f)
+		}
+	}
+}
+
+func TestGenerateListFile(t *testing.T) {
+	tmpFile := "test_list.gen.go"
+	defer os.Remove(tmpFile)
+
+	responses := []ListResponse{
+		{TypeName: "GetAllCustomersResponse", ItemType: "CustomerResponse", EnvelopeType: "CustomerListResponse", Fields: []ListField{
+			{Name: "HasMore", JSONName: "has_more"},
+			{Name: "Object", JSONName: "object", Pointer: true},
+			{Name: "Url", JSONName: "url"},
+		}},
+	}
+
+	if err := generateListFile(tmpFile, responses); err != nil {
+		t.Fatalf("generateListFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	expected := []string{
+		"// Code generated by postprocess. DO NOT EDIT.",
+		"package payjpv2",
+		"func (r *CustomerListResponse) UnmarshalJSON(b []byte) error {\n" +
+			"\ttype plain CustomerListResponse\n" +
+			"\treturn decodeListEnvelope(b, (*plain)(r), &r.Data)\n}",
+		"func (r *GetAllCustomersResponse) Total() (int, bool) {\n\tif r == nil {\n\t\treturn 0, false\n\t}\n\treturn listTotal(r.Body)\n}",
+		"func (r *GetAllCustomersResponse) listResult() *ListResult[CustomerResponse] {\n\tif r == nil || r.Result == nil {\n\t\treturn nil\n\t}\n",
+		"l := newListResult(r.Result.Data, r.Body)\n" +
+			"\tl.hasMore = r.Result.HasMore\n" +
+			"\tif r.Result.Object != nil {\n\t\tl.object = *r.Result.Object\n\t}\n" +
+			"\tl.url = r.Result.Url\n" +
+			"\treturn l\n",
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("generated file missing expected content: %q", exp)
+		}
+	}
+}
+
+func TestExtractZeroStructs(t *testing.T) {
+	content := "type PaymentMethodCardCreateRequest struct {\n" +
+		"\tBillingDetails PaymentMethodCardBillingDetailsRequest `json:\"billing_details\"`\n" +
+		"\tType string `json:\"type\"`\n" +
+		"}\n" +
+		"type PaymentMethodCardBillingDetailsRequest struct {\n" +
+		"\tAddress *PaymentMethodBillingAddressRequest `json:\"address,omitempty\"`\n\n" +
+		"\t// Email 請求先のメールアドレス\n" +
+		"\tEmail *string `json:\"email,omitempty\"`\n" +
+		"}\n" +
+		"type PaymentMethodBillingAddressRequest struct {\n" +
+		"\tCity *string `json:\"city,omitempty\"`\n" +
+		"}\n"
+
+	structs := extractZeroStructs(content)
+
+	if len(structs) != 2 {
+		t.Fatalf("extractZeroStructs() returned %d structs, want 2: %+v", len(structs), structs)
+	}
+	if structs[0].TypeName != "PaymentMethodBillingAddressRequest" || len(structs[0].Fields) != 1 {
+		t.Errorf("structs[0] = %+v", structs[0])
+	}
+	if structs[1].TypeName != "PaymentMethodCardBillingDetailsRequest" || len(structs[1].Fields) != 2 ||
+		structs[1].Fields[1] != (ZeroField{Name: "Email", Type: "*string"}) {
+		t.Errorf("structs[1] = %+v", structs[1])
+	}
+}
+
+func TestGenerateZeroFile(t *testing.T) {
+	tmpFile := "test_zero.gen.go"
+	defer os.Remove(tmpFile)
+
+	structs := []ZeroStruct{
+		{TypeName: "CardDetailsRequest", Fields: []ZeroField{
+			{Name: "Number", Type: "string"},
+			{Name: "ExpMonth", Type: "int"},
+			{Name: "Billing", Type: "BillingRequest"},
+			{Name: "Preference", Type: "DisplayPreferenceRequestPreference"},
+		}},
+		{TypeName: "BillingRequest", Fields: []ZeroField{{Name: "Email", Type: "*string"}}},
+		{TypeName: "EmptyRequest"},
+	}
+
+	if err := generateZeroFile(tmpFile, structs); err != nil {
+		t.Fatalf("generateZeroFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	expected := []string{
+		"// Code generated by postprocess. DO NOT EDIT.",
+		"package payjpv2",
+		"func (r CardDetailsRequest) IsZero() bool {\n\treturn r.Number == \"\" &&\n\t\tr.ExpMonth == 0 &&\n\t\tr.Billing.IsZero() &&\n\t\tisZeroValue(r.Preference)\n}",
+		"func (r BillingRequest) IsZero() bool {\n\treturn r.Email == nil\n}",
+		"func (r EmptyRequest) IsZero() bool {\n\treturn true\n}",
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("generated file missing expected content: %q", exp)
+		}
+	}
+}
+
+func TestExtractResponseTypes(t *testing.T) {
+	content := "// Status returns HTTPResponse.Status\n" +
+		"func (r GetCustomerResponse) Status() string {\n\treturn \"\"\n}\n\n" +
+		"// StatusCode returns HTTPResponse.StatusCode\n" +
+		"func (r GetCustomerResponse) StatusCode() int {\n\treturn 0\n}\n\n" +
+		"func (r GetAllCustomersResponse) StatusCode() int {\n\treturn 0\n}\n\n" +
+		"func (a *APIError) StatusCode() int {\n\treturn 0\n}\n"
+
+	types := extractResponseTypes(content)
+
+	if strings.Join(types, ",") != "GetAllCustomersResponse,GetCustomerResponse" {
+		t.Errorf("extractResponseTypes() = %v, want [GetAllCustomersResponse GetCustomerResponse]", types)
+	}
+}
+
+func TestGenerateResponseFile(t *testing.T) {
+	tmpFile := "test_response.gen.go"
+	defer os.Remove(tmpFile)
+
+	if err := generateResponseFile(tmpFile, []string{"GetAllCustomersResponse", "GetCustomerResponse"}); err != nil {
+		t.Fatalf("generateResponseFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	expected := []string{
+		"// Code generated by postprocess. DO NOT EDIT.",
+		"package payjpv2",
+		"type Response interface {",
+		"\tStatusCode() int\n",
+		"\t_ Response = (*GetAllCustomersResponse)(nil)\n",
+		"\t_ Response = (*GetCustomerResponse)(nil)\n",
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("generated file missing expected content: %q", exp)
+		}
+	}
+}
+
+func TestExtractMoneyStructs(t *testing.T) {
+	content := "type PaymentFlowResponse struct {\n" +
+		"\tAmount int `json:\"amount\"`\n" +
+		"\tAmountReceived int `json:\"amount_received\"`\n" +
+		"\tCurrency  Currency  `json:\"currency\"`\n" +
+		"}\n" +
+		"type PaymentFlowCreateRequest struct {\n" +
+		"\tAmount int `json:\"amount\"`\n" +
+		"\tCurrency Currency `json:\"currency\"`\n" +
+		"}\n" +
+		"type PaymentRefundResponse struct {\n" +
+		"\tAmount int `json:\"amount\"`\n" +
+		"}\n" +
+		"type PriceResponse struct {\n" +
+		"\tCurrency Currency `json:\"currency\"`\n" +
+		"\tUnitAmount int `json:\"unit_amount\"`\n" +
+		"}\n" +
+		"type ProductResponse struct {\n" +
+		"\tAmount *int `json:\"amount,omitempty\"`\n" +
+		"\tCurrency *Currency `json:\"currency,omitempty\"`\n" +
+		"}\n"
+
+	types := extractMoneyStructs(content)
+
+	if strings.Join(types, ",") != "PaymentFlowResponse" {
+		t.Errorf("extractMoneyStructs() = %v, want [PaymentFlowResponse]", types)
+	}
+}
+
+func TestGenerateMoneyFile(t *testing.T) {
+	tmpFile := "test_money.gen.go"
+	defer os.Remove(tmpFile)
+
+	if err := generateMoneyFile(tmpFile, []string{"PaymentDisputeResponse", "PaymentFlowResponse"}); err != nil {
+		t.Fatalf("generateMoneyFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	expected := []string{
+		"// Code generated by postprocess. DO NOT EDIT.",
+		"package payjpv2",
+		"func (r PaymentDisputeResponse) Money() Money {\n\treturn Money{Amount: r.Amount, Currency: r.Currency}\n}",
+		"func (r PaymentFlowResponse) Money() Money {\n\treturn Money{Amount: r.Amount, Currency: r.Currency}\n}",
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("generated file missing expected content: %q", exp)
+		}
+	}
+}
+
+func TestReplaceWithHTTPClient(t *testing.T) {
+	generated := "// WithHTTPClient allows overriding the default Doer, which is\n" +
+		"// automatically created using http.Client. This is useful for tests.\n" +
+		"func WithHTTPClient(doer HttpRequestDoer) ClientOption {\n" +
+		"\treturn func(c *Client) error {\n" +
+		"\t\tc.Client = doer\n" +
+		"\t\treturn nil\n" +
+		"\t}\n" +
+		"}\n"
+	expected := strings.Replace(generated, "c.Client = doer", "setHTTPClient(c, doer)", 1)
+
+	if got := replaceWithHTTPClient(generated); got != expected {
+		t.Errorf("replaceWithHTTPClient() = %q, want %q", got, expected)
+	}
+	if got := replaceWithHTTPClient(expected); got != expected {
+		t.Errorf("replaceWithHTTPClient() changed processed content: %q", got)
+	}
+	other := "func NewClient() {\n\t\tc.Client = doer\n}\n"
+	if got := replaceWithHTTPClient(other); got != other {
+		t.Errorf("replaceWithHTTPClient() changed another function: %q", got)
+	}
+}
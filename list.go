@@ -0,0 +1,104 @@
+package payjpv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// ListResult is a decoded page of a list endpoint.
+type ListResult[T any] struct {
+	// Data is the items of the page
+	Data []T
+
+	hasMore  bool
+	url      string
+	object   string
+	total    int
+	hasTotal bool
+}
+
+// HasMore returns true if there are more items after this page.
+func (l *ListResult[T]) HasMore() bool {
+	return l.hasMore
+}
+
+// URL returns the URL of the list, e.g. /v2/customers.
+func (l *ListResult[T]) URL() string {
+	return l.url
+}
+
+// Object returns the object type of the list envelope, "list".
+func (l *ListResult[T]) Object() string {
+	return l.object
+}
+
+// Total returns the total number of items across all pages, if the endpoint reports one
+// in a "total" or "count" field.
+func (l *ListResult[T]) Total() (int, bool) {
+	return l.total, l.hasTotal
+}
+
+// listResponse is implemented by the generated list responses (see list.gen.go)
+type listResponse[T any] interface {
+	Total() (int, bool)
+	listResult() *ListResult[T]
+}
+
+// newListResult builds a ListResult from a decoded page and its raw body
+func newListResult[T any](data []T, body []byte) *ListResult[T] {
+	l := &ListResult[T]{Data: data}
+	l.total, l.hasTotal = listTotal(body)
+	return l
+}
+
+// listTotal reads the optional "total" or "count" field of a list response body
+func listTotal(body []byte) (int, bool) {
+	var counts struct {
+		Total *int `json:"total"`
+		Count *int `json:"count"`
+	}
+	if err := json.Unmarshal(body, &counts); err != nil {
+		return 0, false
+	}
+	if counts.Total != nil {
+		return *counts.Total, true
+	}
+	if counts.Count != nil {
+		return *counts.Count, true
+	}
+	return 0, false
+}
+
+// decodeListEnvelope decodes a list envelope into env. A bare JSON array, which some endpoints
+// or proxies return instead of the envelope, is decoded into data as a single page without
+// has_more, url or object.
+func decodeListEnvelope[T any](b []byte, env any, data *[]T) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, data)
+	}
+	return json.Unmarshal(b, env)
+}
+
+// ExtractList is like Extract for list endpoints: it returns API errors as an error and
+// otherwise the page as a ListResult, including the total count when the endpoint reports one.
+//
+// Example usage:
+//
+//	customers, err := payjpv2.ExtractList(client.GetAllCustomersWithResponse(ctx, params))
+//	if err != nil {
+//	    return err
+//	}
+//	if total, ok := customers.Total(); ok {
+//	    fmt.Printf("%d of %d customers\n", len(customers.Data), total)
+//	}
+func ExtractList[R listResponse[T], T any](resp R, err error) (*ListResult[T], error) {
+	if _, err := Extract(resp, err); err != nil {
+		return nil, err
+	}
+	list := resp.listResult()
+	if list == nil {
+		return nil, errors.New("list response has no result")
+	}
+	return list, nil
+}
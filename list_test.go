@@ -0,0 +1,128 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestExtractList(t *testing.T) {
+	newClient := func(t *testing.T, status int, body string) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(status, body), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("surfaces the count field", func(t *testing.T) {
+		client := newClient(t, 200, `{"object":"list","data":[{"id":"cus_1"},{"id":"cus_2"}],"has_more":true,"url":"/v2/customers","count":42}`)
+
+		resp, err := client.GetAllCustomersWithResponse(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if total, ok := resp.Total(); !ok || total != 42 {
+			t.Errorf("Total incorrect. Got: %d, %v, Expected: 42, true", total, ok)
+		}
+
+		customers, err := ExtractList(resp, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(customers.Data) != 2 || customers.Data[1].Id != "cus_2" {
+			t.Errorf("Data incorrect. Got: %+v", customers.Data)
+		}
+		if total, ok := customers.Total(); !ok || total != 42 {
+			t.Errorf("Total incorrect. Got: %d, %v, Expected: 42, true", total, ok)
+		}
+		if !customers.HasMore() {
+			t.Error("Expected HasMore to be true")
+		}
+		if customers.URL() != "/v2/customers" {
+			t.Errorf("URL incorrect. Got: %s, Expected: /v2/customers", customers.URL())
+		}
+		if customers.Object() != "list" {
+			t.Errorf("Object incorrect. Got: %s, Expected: list", customers.Object())
+		}
+	})
+
+	t.Run("surfaces the total field", func(t *testing.T) {
+		client := newClient(t, 200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers","total":0}`)
+
+		customers, err := ExtractList(client.GetAllCustomersWithResponse(context.Background(), nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if total, ok := customers.Total(); !ok || total != 0 {
+			t.Errorf("Total incorrect. Got: %d, %v, Expected: 0, true", total, ok)
+		}
+	})
+
+	t.Run("reports no total when absent", func(t *testing.T) {
+		client := newClient(t, 200, `{"object":"list","data":[{"id":"cus_1"}],"has_more":false,"url":"/v2/customers"}`)
+
+		customers, err := ExtractList(client.GetAllCustomersWithResponse(context.Background(), nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(customers.Data) != 1 {
+			t.Errorf("Data incorrect. Got: %+v", customers.Data)
+		}
+		if customers.HasMore() {
+			t.Error("Expected HasMore to be false")
+		}
+		if _, ok := customers.Total(); ok {
+			t.Error("Expected no total")
+		}
+	})
+
+	t.Run("decodes a bare array body", func(t *testing.T) {
+		client := newClient(t, 200, `[{"id":"cus_1","object":"customer"},{"id":"cus_2","object":"customer"}]`)
+
+		resp, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Result == nil || len(resp.Result.Data) != 2 {
+			t.Fatalf("Result incorrect. Got: %+v", resp.Result)
+		}
+
+		customers, err := ExtractList(resp, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(customers.Data) != 2 || customers.Data[0].Id != "cus_1" || customers.Data[1].Id != "cus_2" {
+			t.Errorf("Data incorrect. Got: %+v", customers.Data)
+		}
+		if customers.HasMore() {
+			t.Error("Expected HasMore to be false")
+		}
+		if _, ok := customers.Total(); ok {
+			t.Error("Expected no total")
+		}
+	})
+
+	t.Run("returns an error for a nil response", func(t *testing.T) {
+		customers, err := ExtractList((*GetAllCustomersResponse)(nil), nil)
+		if err == nil || customers != nil {
+			t.Errorf("Expected an error and no result, got: %+v, %v", customers, err)
+		}
+	})
+
+	t.Run("returns API errors", func(t *testing.T) {
+		client := newClient(t, 400, `{"status":400,"title":"Bad Request","type":"about:blank"}`)
+
+		_, err := ExtractList(client.GetAllCustomersWithResponse(context.Background(), nil))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.IsBadRequest() {
+			t.Errorf("Expected bad request APIError, got: %v", err)
+		}
+	})
+}
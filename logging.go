@@ -0,0 +1,164 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// RequestLog describes an outgoing request passed to Logger.LogRequest.
+type RequestLog struct {
+	// Method is the HTTP method of the request
+	Method string
+	// URL is the full URL of the request
+	URL string
+	// Header is a copy of the request headers, with sensitive values redacted unless
+	// WithLogRawHeaders is used
+	Header http.Header
+	// ClientRequestID is the id set by WithRequestIDGenerator, if any
+	ClientRequestID string
+}
+
+// ResponseLog describes the outcome of a request passed to Logger.LogResponse.
+type ResponseLog struct {
+	RequestLog
+	// StatusCode is the HTTP status code of the response, 0 if no response was received
+	StatusCode int
+	// Duration is the time from sending the request until the response headers were received
+	Duration time.Duration
+	// Err is the transport error, if no response was received
+	Err error
+}
+
+// Logger receives a log entry for every request sent by the client, including each retry.
+type Logger interface {
+	// LogRequest is called before the request is sent
+	LogRequest(ctx context.Context, entry RequestLog)
+	// LogResponse is called once the response headers are received or the request failed
+	LogResponse(ctx context.Context, entry ResponseLog)
+}
+
+// redactedHeaders are the headers whose values are replaced by redactedValue in log entries
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Idempotency-Key"}
+
+const redactedValue = "[REDACTED]"
+
+// WithLogger returns a ClientOption that passes every request and its outcome to logger.
+// The Authorization and Idempotency-Key headers are redacted unless WithLogRawHeaders is used.
+// Durations are measured with the client's clock.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithLogger(payjpv2.NewSlogLogger(slog.Default())),
+//	)
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			return errors.New("logger cannot be nil")
+		}
+		sdkDoerFor(c).logger = logger
+		return nil
+	}
+}
+
+// WithLogRawHeaders returns a ClientOption that passes request headers to the Logger set with
+// WithLogger without redacting them. Only use it where the logs are as protected as the API key.
+func WithLogRawHeaders() ClientOption {
+	return func(c *Client) error {
+		sdkDoerFor(c).logRawHeaders = true
+		return nil
+	}
+}
+
+// requestLog builds the log entry of req
+func (d *sdkDoer) requestLog(req *http.Request) RequestLog {
+	header := req.Header.Clone()
+	if !d.logRawHeaders {
+		for _, name := range redactedHeaders {
+			if header.Get(name) != "" {
+				header.Set(name, redactedValue)
+			}
+		}
+	}
+	return RequestLog{
+		Method:          req.Method,
+		URL:             req.URL.String(),
+		Header:          header,
+		ClientRequestID: ClientRequestID(req),
+	}
+}
+
+// logged sends req with send, passing the request and its outcome to the logger of d, if any
+func (d *sdkDoer) logged(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if d.logger == nil {
+		return send(req)
+	}
+	clock := d.clockOrDefault()
+	entry := d.requestLog(req)
+	d.logger.LogRequest(req.Context(), entry)
+
+	start := clock.Now()
+	resp, err := send(req)
+	result := ResponseLog{RequestLog: entry, Duration: clock.Now().Sub(start), Err: err}
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+	d.logger.LogResponse(req.Context(), result)
+	return resp, err
+}
+
+// slogLogger is the Logger returned by NewSlogLogger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger that writes requests, with their headers, at debug level and
+// responses at info level to logger, or to slog.Default() if logger is nil. Failed requests are
+// written at error level.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// LogRequest implements Logger.
+func (l *slogLogger) LogRequest(ctx context.Context, entry RequestLog) {
+	names := make([]string, 0, len(entry.Header))
+	for name := range entry.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	headers := make([]any, len(names))
+	for i, name := range names {
+		headers[i] = slog.Any(name, entry.Header[name])
+	}
+	attrs := append(requestAttrs(entry), slog.Group("headers", headers...))
+	l.logger.LogAttrs(ctx, slog.LevelDebug, "payjp request", attrs...)
+}
+
+// LogResponse implements Logger.
+func (l *slogLogger) LogResponse(ctx context.Context, entry ResponseLog) {
+	attrs := append(requestAttrs(entry.RequestLog), slog.Duration("duration", entry.Duration))
+	if entry.Err != nil {
+		l.logger.LogAttrs(ctx, slog.LevelError, "payjp request failed", append(attrs, slog.Any("error", entry.Err))...)
+		return
+	}
+	l.logger.LogAttrs(ctx, slog.LevelInfo, "payjp response", append(attrs, slog.Int("status", entry.StatusCode))...)
+}
+
+// requestAttrs returns the slog attributes of a request log entry
+func requestAttrs(entry RequestLog) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", entry.Method),
+		slog.String("url", entry.URL),
+	}
+	if entry.ClientRequestID != "" {
+		attrs = append(attrs, slog.String("client_request_id", entry.ClientRequestID))
+	}
+	return attrs
+}
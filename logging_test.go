@@ -0,0 +1,152 @@
+package payjpv2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// recordingLogger is a Logger that records its entries
+type recordingLogger struct {
+	requests  []RequestLog
+	responses []ResponseLog
+}
+
+func (l *recordingLogger) LogRequest(ctx context.Context, entry RequestLog) {
+	l.requests = append(l.requests, entry)
+}
+
+func (l *recordingLogger) LogResponse(ctx context.Context, entry ResponseLog) {
+	l.responses = append(l.responses, entry)
+}
+
+func TestWithLogger(t *testing.T) {
+	clock := newFakeClock()
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		clock.advance(250 * time.Millisecond)
+		return jsonResponse(200, `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`), nil
+	})
+	email := openapi_types.Email("taro@example.com")
+	req := CustomerCreateRequest{Email: &email}
+
+	t.Run("logs requests and responses with redacted headers", func(t *testing.T) {
+		logger := &recordingLogger{}
+		client, err := NewPayjpClientWithResponses("sk_test_secret",
+			WithHTTPClient(&http.Client{Transport: transport}),
+			WithClock(clock),
+			WithLogger(logger),
+			WithRequestIDGenerator(func() string { return "req_1" }),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if _, err := client.CreateCustomerWithResponse(context.Background(), req, WithIdempotencyKey("idem_1")); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(logger.requests) != 1 || len(logger.responses) != 1 {
+			t.Fatalf("Entry count incorrect. Got: %d requests, %d responses, Expected: 1, 1", len(logger.requests), len(logger.responses))
+		}
+		entry := logger.requests[0]
+		if entry.Method != http.MethodPost || entry.URL != "https://api.pay.jp/v2/customers" {
+			t.Errorf("Request incorrect. Got: %s %s", entry.Method, entry.URL)
+		}
+		for _, name := range []string{"Authorization", "Idempotency-Key"} {
+			if got := entry.Header.Get(name); got != "[REDACTED]" {
+				t.Errorf("%s header incorrect. Got: %s, Expected: [REDACTED]", name, got)
+			}
+		}
+		if entry.ClientRequestID != "req_1" {
+			t.Errorf("Client request id incorrect. Got: %s, Expected: req_1", entry.ClientRequestID)
+		}
+
+		resp := logger.responses[0]
+		if resp.StatusCode != 200 || resp.Duration != 250*time.Millisecond || resp.Err != nil {
+			t.Errorf("Response incorrect. Got: %d, %s, %v, Expected: 200, 250ms, nil", resp.StatusCode, resp.Duration, resp.Err)
+		}
+	})
+
+	t.Run("logs raw headers when asked", func(t *testing.T) {
+		logger := &recordingLogger{}
+		client, err := NewPayjpClientWithResponses("sk_test_secret",
+			WithHTTPClient(&http.Client{Transport: transport}),
+			WithLogger(logger),
+			WithLogRawHeaders(),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if _, err := client.CreateCustomerWithResponse(context.Background(), req, WithIdempotencyKey("idem_1")); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		header := logger.requests[0].Header
+		if got := header.Get("Authorization"); got != "Bearer sk_test_secret" {
+			t.Errorf("Authorization header incorrect. Got: %s, Expected: Bearer sk_test_secret", got)
+		}
+		if got := header.Get("Idempotency-Key"); got != "idem_1" {
+			t.Errorf("Idempotency-Key header incorrect. Got: %s, Expected: idem_1", got)
+		}
+	})
+
+	t.Run("logs transport errors", func(t *testing.T) {
+		logger := &recordingLogger{}
+		client, err := NewPayjpClientWithResponses("sk_test_secret",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			})}),
+			WithLogger(logger),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if len(logger.responses) != 1 || logger.responses[0].Err == nil || logger.responses[0].StatusCode != 0 {
+			t.Errorf("Expected a failed response entry, got: %+v", logger.responses)
+		}
+	})
+
+	t.Run("rejects a nil logger", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_secret", WithLogger(nil)); err == nil {
+			t.Error("Expected error for nil logger")
+		}
+	})
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	client, err := NewPayjpClientWithResponses("sk_test_secret",
+		WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`), nil
+		})}),
+		WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+	out := buf.String()
+	for _, expected := range []string{
+		`msg="payjp request" method=GET url=https://api.pay.jp/v2/customers/cus_1`,
+		`headers.Authorization=[[REDACTED]]`,
+		`msg="payjp response" method=GET url=https://api.pay.jp/v2/customers/cus_1 duration=`,
+		`status=404`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Log output missing %q. Got: %s", expected, out)
+		}
+	}
+	if strings.Contains(out, "sk_test_secret") {
+		t.Errorf("Log output contains the API key: %s", out)
+	}
+}
@@ -0,0 +1,85 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// metadataCreateOperations are the create operations whose request body has a metadata field.
+// Products have no metadata.
+var metadataCreateOperations = []string{
+	"POST /v2/checkout/sessions",
+	"POST /v2/customers",
+	"POST /v2/payment_flows",
+	"POST /v2/payment_methods",
+	"POST /v2/payment_refunds",
+	"POST /v2/prices",
+	"POST /v2/setup_flows",
+	"POST /v2/tax_rates",
+}
+
+// WithDefaultMetadata returns a ClientOption that adds md to the metadata of every created
+// resource that has metadata, e.g. to tag resources with the platform that created them.
+// Metadata keys set on the request take precedence over md. Updates are left unchanged.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithDefaultMetadata(map[string]string{"source": "our-platform"}),
+//	)
+func WithDefaultMetadata(md map[string]string) ClientOption {
+	if len(md) == 0 {
+		return func(c *Client) error {
+			return errors.New("default metadata cannot be empty")
+		}
+	}
+	defaults := maps.Clone(md)
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		for _, operation := range metadataCreateOperations {
+			if matchOperation(req, operation) {
+				return rewriteJSONBody(req, func(fields map[string]json.RawMessage) bool {
+					return mergeMetadata(fields, defaults)
+				})
+			}
+		}
+		return nil
+	})
+}
+
+// mergeMetadata adds the keys of defaults missing from the metadata field and reports whether
+// the metadata changed. A metadata field that is not an object is left unchanged.
+func mergeMetadata(fields map[string]json.RawMessage, defaults map[string]string) bool {
+	metadata := map[string]json.RawMessage{}
+	if raw, ok := fields["metadata"]; ok {
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			return false
+		}
+		if metadata == nil {
+			metadata = map[string]json.RawMessage{}
+		}
+	}
+	changed := false
+	for key, value := range defaults {
+		if _, ok := metadata[key]; ok {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		metadata[key] = raw
+		changed = true
+	}
+	if !changed {
+		return false
+	}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return false
+	}
+	fields["metadata"] = raw
+	return true
+}
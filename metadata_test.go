@@ -0,0 +1,79 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithDefaultMetadata(t *testing.T) {
+	newClient := func(t *testing.T, sent *map[string]any) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example",
+			WithDefaultMetadata(map[string]string{"source": "our-platform", "team": "billing"}),
+			WithHTTPClient(&http.Client{
+				Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					data, _ := io.ReadAll(req.Body)
+					*sent = nil
+					if err := json.Unmarshal(data, sent); err != nil {
+						t.Errorf("Failed to decode sent body %s: %v", data, err)
+					}
+					return jsonResponse(200, `{}`), nil
+				}),
+			}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("adds the default metadata to a create body", func(t *testing.T) {
+		var sent map[string]any
+		email := "taro@example.com"
+		_, err := newClient(t, &sent).CreateCustomerWithBodyWithResponse(context.Background(), "application/json", strings.NewReader(`{"email":"`+email+`"}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		metadata, _ := sent["metadata"].(map[string]any)
+		if metadata["source"] != "our-platform" || metadata["team"] != "billing" {
+			t.Errorf("Metadata incorrect. Got: %v", sent["metadata"])
+		}
+		if sent["email"] != email {
+			t.Errorf("Email incorrect. Got: %v, Expected: %s", sent["email"], email)
+		}
+	})
+
+	t.Run("keeps keys set on the request", func(t *testing.T) {
+		var sent map[string]any
+		body := `{"amount":1000,"currency":"jpy","metadata":{"source":"import","order":42}}`
+		_, err := newClient(t, &sent).CreatePaymentFlowWithBodyWithResponse(context.Background(), "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		metadata, _ := sent["metadata"].(map[string]any)
+		if metadata["source"] != "import" || metadata["order"] != float64(42) || metadata["team"] != "billing" {
+			t.Errorf("Metadata incorrect. Got: %v", sent["metadata"])
+		}
+	})
+
+	t.Run("leaves updates unchanged", func(t *testing.T) {
+		var sent map[string]any
+		_, err := newClient(t, &sent).UpdateCustomerWithBodyWithResponse(context.Background(), "cus_1", "application/json", strings.NewReader(`{"description":"x"}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, ok := sent["metadata"]; ok {
+			t.Errorf("Expected no metadata on update, got: %v", sent["metadata"])
+		}
+	})
+
+	t.Run("rejects empty metadata", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_example", WithDefaultMetadata(nil)); err == nil {
+			t.Error("Expected error for empty default metadata")
+		}
+	})
+}
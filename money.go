@@ -0,0 +1,37 @@
+package payjpv2
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in the smallest unit of its currency, e.g. yen for jpy.
+// The response types with an amount and currency pair return it from their Money method
+// (see money.gen.go).
+type Money struct {
+	Amount   int      `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// String returns the amount followed by the upper-case currency code, e.g. "1000 JPY".
+func (m Money) String() string {
+	if m.Currency == "" {
+		return strconv.Itoa(m.Amount)
+	}
+	return strconv.Itoa(m.Amount) + " " + strings.ToUpper(string(m.Currency))
+}
+
+// IsZero reports whether the amount is zero, whatever the currency.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// UnmarshalJSON decodes Money, accepting the amount encoded as a numeric string
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b, err := normalizeAmountFields(b, "amount")
+	if err != nil {
+		return err
+	}
+	type plain Money
+	return decodeJSON(b, (*plain)(m))
+}
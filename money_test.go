@@ -0,0 +1,81 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	t.Run("round-trips a payment flow amount", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(200, `{"id":"pfw_1","amount":"1000","amount_received":0,"currency":"jpy","status":"succeeded"}`), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		resp, err := Extract(client.GetPaymentFlowWithResponse(context.Background(), "pfw_1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		money := resp.Result.Money()
+		if money != (Money{Amount: 1000, Currency: CurrencyJpy}) {
+			t.Errorf("Money incorrect. Got: %+v, Expected: 1000 jpy", money)
+		}
+
+		data, err := json.Marshal(money)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(data) != `{"amount":1000,"currency":"jpy"}` {
+			t.Errorf("JSON incorrect. Got: %s, Expected: {\"amount\":1000,\"currency\":\"jpy\"}", data)
+		}
+
+		var decoded Money
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded != money {
+			t.Errorf("Decoded money incorrect. Got: %+v, Expected: %+v", decoded, money)
+		}
+	})
+
+	t.Run("decodes a string amount", func(t *testing.T) {
+		var m Money
+		if err := json.Unmarshal([]byte(`{"amount":"500","currency":"jpy"}`), &m); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if m != (Money{Amount: 500, Currency: CurrencyJpy}) {
+			t.Errorf("Money incorrect. Got: %+v, Expected: 500 jpy", m)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		tests := []struct {
+			money    Money
+			expected string
+		}{
+			{Money{Amount: 1000, Currency: CurrencyJpy}, "1000 JPY"},
+			{Money{Amount: 0, Currency: CurrencyJpy}, "0 JPY"},
+			{Money{Amount: 42}, "42"},
+		}
+		for _, tt := range tests {
+			if got := tt.money.String(); got != tt.expected {
+				t.Errorf("String incorrect. Got: %s, Expected: %s", got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("IsZero", func(t *testing.T) {
+		if !(Money{}).IsZero() || !(Money{Currency: CurrencyJpy}).IsZero() {
+			t.Error("Expected zero amounts to be zero")
+		}
+		if (Money{Amount: 1, Currency: CurrencyJpy}).IsZero() {
+			t.Error("Expected a non-zero amount not to be zero")
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// listPageLimit is the page size used by the SDK's list iterators (the API maximum)
+const listPageLimit = 100
+
+// ErrMaxPagesExceeded is yielded by the list iterators when a list still has more pages after
+// the number of pages set with WithMaxPages.
+var ErrMaxPagesExceeded = errors.New("maximum number of pages exceeded")
+
+// WithMaxPages returns a ClientOption that limits the SDK's list iterators to n pages. When the
+// list reports more pages after the n-th, the iterator yields ErrMaxPagesExceeded and stops. This
+// guards against endless pagination if the API keeps reporting has_more.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey, payjpv2.WithMaxPages(1000))
+func WithMaxPages(n int) ClientOption {
+	return func(c *Client) error {
+		if n < 1 {
+			return errors.New("max pages must be positive")
+		}
+		sdkDoerFor(c).maxPages = n
+		return nil
+	}
+}
+
+// maxPagesOf returns the page limit of c's list iterators, or 0 if there is none
+func (c *ClientWithResponses) maxPagesOf() int {
+	if client, ok := c.ClientInterface.(*Client); ok {
+		if d, ok := client.Client.(*sdkDoer); ok {
+			return d.maxPages
+		}
+	}
+	return 0
+}
+
+// paginate returns an iterator over the items of a list endpoint. fetch is called with the
+// starting_after cursor (nil for the first page) and returns the page's items and has_more;
+// id returns the cursor of an item. The context is checked before each page is fetched, and
+// iteration stops after the first error is yielded. If maxPages is positive, at most that many
+// pages are fetched and ErrMaxPagesExceeded is yielded if more remain.
+func paginate[T any](ctx context.Context, maxPages int, fetch func(startingAfter *string) ([]T, bool, error), id func(*T) string) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		var cursor *string
+		for pages := 0; ; pages++ {
+			if maxPages > 0 && pages == maxPages {
+				yield(nil, ErrMaxPagesExceeded)
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			items, hasMore, err := fetch(cursor)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range items {
+				if !yield(&items[i], nil) {
+					return
+				}
+			}
+			if !hasMore || len(items) == 0 {
+				return
+			}
+			last := id(&items[len(items)-1])
+			if last == "" {
+				yield(nil, fmt.Errorf("failed to read pagination cursor: last item has no id"))
+				return
+			}
+			cursor = &last
+		}
+	}
+}
+
+// Iterator pages through a list endpoint, fetching each page when the items of the previous one
+// have been consumed. Call Next until it returns false, then check Err.
+//
+// Example usage:
+//
+//	it := payjpv2.Customers(client).Iterate(ctx, nil)
+//	for it.Next(ctx) {
+//	    fmt.Println(it.Item().Id)
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    func(ctx context.Context, startingAfter *string) ([]T, bool, error)
+	id       func(*T) string
+	limit    int
+	maxPages int
+
+	page     []T
+	index    int
+	pages    int
+	cursor   *string
+	lastPage bool
+	err      error
+}
+
+// newIterator returns an Iterator over the pages returned by fetch, which are at most limit items
+// long. ctx bounds the whole iteration.
+func newIterator[T any](ctx context.Context, limit, maxPages int, fetch func(ctx context.Context, startingAfter *string) ([]T, bool, error), id func(*T) string) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, id: id, limit: limit, maxPages: maxPages, index: -1}
+}
+
+// Next advances to the next item, fetching the next page with ctx if needed. It returns false
+// when the list is exhausted or an error occurred. Before a page is fetched, ctx and the context
+// the iterator was created with are checked, and a done context stops the iteration with its
+// error. The list is exhausted after a page without has_more or with fewer items than the page
+// size.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.page) {
+		it.index++
+		return true
+	}
+	if it.lastPage {
+		return false
+	}
+	if it.maxPages > 0 && it.pages == it.maxPages {
+		it.err = ErrMaxPagesExceeded
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	items, hasMore, err := it.fetch(ctx, it.cursor)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.pages++
+	it.page, it.index = items, 0
+	if !hasMore || len(items) < it.limit {
+		it.lastPage = true
+	}
+	if len(items) == 0 {
+		it.lastPage = true
+		return false
+	}
+	if !it.lastPage {
+		last := it.id(&items[len(items)-1])
+		if last == "" {
+			it.err = fmt.Errorf("failed to read pagination cursor: last item has no id")
+			return false
+		}
+		it.cursor = &last
+	}
+	return true
+}
+
+// Item returns the current item. It is only valid after Next has returned true.
+func (it *Iterator[T]) Item() T {
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, or nil if the list was exhausted.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// rawListPage is the envelope shared by every PAY.JP list response
+type rawListPage struct {
+	Data    []json.RawMessage `json:"data"`
+	HasMore bool              `json:"has_more"`
+}
+
+// FollowPages walks a paginated list starting from a raw HTTP response, calling fn for each item.
+// While the page reports has_more, next is called with the ID of the last item on the page
+// (the starting_after cursor) to fetch the following page. Response bodies are closed.
+// A nil response, first or from next, fails the walk with an error.
+//
+// This lets advanced users paginate endpoints that the typed helpers don't cover yet.
+//
+// Example usage:
+//
+//	err := payjpv2.FollowPages(ctx, first, func(cursor string) (*http.Response, error) {
+//	    return client.GetAllCustomers(ctx, &payjpv2.GetAllCustomersParams{StartingAfter: &cursor})
+//	}, func(item json.RawMessage) error {
+//	    fmt.Println(string(item))
+//	    return nil
+//	})
+func FollowPages(ctx context.Context, first *http.Response, next func(cursor string) (*http.Response, error), fn func(json.RawMessage) error) error {
+	resp := first
+	for {
+		page, err := readRawListPage(resp)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Data {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if !page.HasMore || len(page.Data) == 0 {
+			return nil
+		}
+
+		var last struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(page.Data[len(page.Data)-1], &last); err != nil {
+			return fmt.Errorf("failed to read pagination cursor: %w", err)
+		}
+		if last.ID == "" {
+			return fmt.Errorf("failed to read pagination cursor: last item has no id")
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err = next(last.ID)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readRawListPage reads and closes the body of a list response.
+// Error responses are returned as *APIError.
+func readRawListPage(resp *http.Response) (*rawListPage, error) {
+	if resp == nil || resp.Body == nil {
+		return nil, errors.New("list response has no body")
+	}
+	body, err := io.ReadAll(resp.Body)
+	defer func() { _ = resp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+	if apiErr := apiErrorFromHTTPResponse(resp, body); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var page rawListPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("failed to decode list response: %w", err)
+	}
+	return &page, nil
+}
+
+// apiErrorFromHTTPResponse returns an APIError for a raw response with an error status code,
+// decoding the problem+json body when possible. It returns nil for successful responses.
+func apiErrorFromHTTPResponse(resp *http.Response, body []byte) *APIError {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	return newAPIError(resp.StatusCode, problemOf(body), body, resp)
+}
+
+// problemOf decodes a problem+json error body, returning nil if body is not one
+func problemOf(body []byte) *ErrorResponse {
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Title == "" {
+		return nil
+	}
+	return &errResp
+}
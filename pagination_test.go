@@ -0,0 +1,149 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// jsonResponse builds an *http.Response with a JSON body for tests
+func jsonResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestFollowPages(t *testing.T) {
+	t.Run("walks two raw pages", func(t *testing.T) {
+		first := jsonResponse(200, `{"object":"list","data":[{"id":"cus_1"},{"id":"cus_2"}],"has_more":true,"url":"/v2/customers"}`)
+		var cursors []string
+		next := func(cursor string) (*http.Response, error) {
+			cursors = append(cursors, cursor)
+			return jsonResponse(200, `{"object":"list","data":[{"id":"cus_3"}],"has_more":false,"url":"/v2/customers"}`), nil
+		}
+
+		var ids []string
+		err := FollowPages(context.Background(), first, next, func(item json.RawMessage) error {
+			var c struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(item, &c); err != nil {
+				return err
+			}
+			ids = append(ids, c.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if strings.Join(ids, ",") != "cus_1,cus_2,cus_3" {
+			t.Errorf("Items incorrect. Got: %v", ids)
+		}
+		if len(cursors) != 1 || cursors[0] != "cus_2" {
+			t.Errorf("Cursors incorrect. Got: %v, Expected: [cus_2]", cursors)
+		}
+	})
+
+	t.Run("returns an error for a nil response", func(t *testing.T) {
+		first := jsonResponse(200, `{"data":[{"id":"cus_1"}],"has_more":true}`)
+		next := func(cursor string) (*http.Response, error) {
+			return nil, nil
+		}
+
+		if err := FollowPages(context.Background(), first, next, func(json.RawMessage) error { return nil }); err == nil {
+			t.Error("Expected error for a nil next page, got nil")
+		}
+		if err := FollowPages(context.Background(), nil, next, func(json.RawMessage) error { return nil }); err == nil {
+			t.Error("Expected error for a nil first page, got nil")
+		}
+	})
+
+	t.Run("returns APIError for error page", func(t *testing.T) {
+		first := jsonResponse(200, `{"data":[{"id":"cus_1"}],"has_more":true}`)
+		next := func(cursor string) (*http.Response, error) {
+			return jsonResponse(400, `{"status":400,"title":"Bad Request","type":"about:blank"}`), nil
+		}
+
+		err := FollowPages(context.Background(), first, next, func(json.RawMessage) error { return nil })
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected *APIError, got: %v", err)
+		}
+		if !apiErr.IsBadRequest() || apiErr.Body == nil || apiErr.Body.Title != "Bad Request" {
+			t.Errorf("Unexpected APIError: %+v", apiErr)
+		}
+	})
+
+	t.Run("stops when context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		first := jsonResponse(200, `{"data":[{"id":"cus_1"}],"has_more":true}`)
+		next := func(cursor string) (*http.Response, error) {
+			t.Error("next should not be called after cancellation")
+			return nil, nil
+		}
+
+		err := FollowPages(ctx, first, next, func(json.RawMessage) error {
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got: %v", err)
+		}
+	})
+}
+
+func TestWithMaxPages(t *testing.T) {
+	newClient := func(t *testing.T, requests *int, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				*requests++
+				id := fmt.Sprintf("cus_%d", *requests)
+				return jsonResponse(200, `{"object":"list","url":"/v2/customers","has_more":true,"data":[{"id":"`+id+`","object":"customer","livemode":false,"metadata":{}}]}`), nil
+			}),
+		})}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("stops at the page cap", func(t *testing.T) {
+		var requests int
+		client := newClient(t, &requests, WithMaxPages(3))
+
+		var ids []string
+		var lastErr error
+		for customer, err := range client.customers(context.Background(), nil) {
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			ids = append(ids, customer.Id)
+		}
+		if !errors.Is(lastErr, ErrMaxPagesExceeded) {
+			t.Errorf("Expected ErrMaxPagesExceeded, got: %v", lastErr)
+		}
+		if requests != 3 {
+			t.Errorf("Request count incorrect. Got: %d, Expected: 3", requests)
+		}
+		if got := strings.Join(ids, ","); got != "cus_1,cus_2,cus_3" {
+			t.Errorf("Customers incorrect. Got: %s, Expected: cus_1,cus_2,cus_3", got)
+		}
+	})
+
+	t.Run("rejects a non-positive cap", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_example", WithMaxPages(0)); err == nil {
+			t.Error("Expected error for zero max pages")
+		}
+	})
+}
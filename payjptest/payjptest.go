@@ -0,0 +1,64 @@
+// Package payjptest provides helpers for unit-testing code that handles PAY.JP webhook events,
+// without a PAY.JP account or network access.
+package payjptest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	payjpv2 "github.com/payjp/payjpv2-go"
+)
+
+// NewEvent returns a test mode event of type eventType (e.g. "customer.created") with a random
+// id, whose data is data encoded as JSON. It panics if data cannot be encoded as a JSON object.
+//
+// Example usage:
+//
+//	event := payjptest.NewEvent("customer.created", payjpv2.CustomerResponse{Id: "cus_1"})
+func NewEvent(eventType string, data any) *payjpv2.EventResponse {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		panic("payjptest: failed to encode event data: " + err.Error())
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		panic("payjptest: event data is not a JSON object: " + err.Error())
+	}
+
+	id := make([]byte, 12)
+	_, _ = rand.Read(id)
+	object := "event"
+	now := time.Now().UTC().Truncate(time.Second)
+	return &payjpv2.EventResponse{
+		Id:        "evnt_test_" + hex.EncodeToString(id),
+		Object:    &object,
+		Type:      eventType,
+		Data:      fields,
+		Livemode:  false,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NewRequest returns an incoming webhook request delivering event, for passing to a handler
+// that reads it with ClientWithResponses.ConstructEventFromRequest. It panics if event cannot
+// be encoded.
+//
+// Example usage:
+//
+//	rec := httptest.NewRecorder()
+//	handleWebhook(rec, payjptest.NewRequest(payjptest.NewEvent("customer.created", customer)))
+func NewRequest(event *payjpv2.EventResponse) *http.Request {
+	body, err := json.Marshal(event)
+	if err != nil {
+		panic("payjptest: failed to encode event: " + err.Error())
+	}
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payjp", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
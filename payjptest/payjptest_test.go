@@ -0,0 +1,51 @@
+package payjptest
+
+import (
+	"strings"
+	"testing"
+
+	payjpv2 "github.com/payjp/payjpv2-go"
+)
+
+func TestNewEvent(t *testing.T) {
+	t.Run("builds a test mode event", func(t *testing.T) {
+		event := NewEvent("customer.created", payjpv2.CustomerResponse{Id: "cus_1"})
+		if event.Type != "customer.created" {
+			t.Errorf("Type incorrect. Got: %s, Expected: customer.created", event.Type)
+		}
+		if !strings.HasPrefix(event.Id, "evnt_test_") {
+			t.Errorf("Id incorrect. Got: %s, Expected: evnt_test_ prefix", event.Id)
+		}
+		if event.Livemode {
+			t.Error("Expected a test mode event")
+		}
+		if event.Data["id"] != "cus_1" {
+			t.Errorf("Data incorrect. Got: %v", event.Data)
+		}
+	})
+
+	t.Run("uses a new id for each event", func(t *testing.T) {
+		if NewEvent("customer.created", nil).Id == NewEvent("customer.created", nil).Id {
+			t.Error("Expected distinct ids")
+		}
+	})
+}
+
+func TestNewRequest(t *testing.T) {
+	client, err := payjpv2.NewPayjpClientWithResponses("sk_test_example")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	event := NewEvent("payment_flow.succeeded", map[string]any{"id": "pfw_1", "amount": 1000})
+
+	got, err := client.ConstructEventFromRequest(NewRequest(event), payjpv2.WithEventModeCheck())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Id != event.Id || got.Type != "payment_flow.succeeded" {
+		t.Errorf("Event incorrect. Got: %s %s, Expected: %s payment_flow.succeeded", got.Id, got.Type, event.Id)
+	}
+	if amount, ok := payjpv2.NumberInt64(got.Data["amount"]); !ok || amount != 1000 {
+		t.Errorf("Amount incorrect. Got: %v", got.Data["amount"])
+	}
+}
@@ -2,13 +2,19 @@ package payjpv2
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"path"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -43,6 +49,25 @@ func WithXPayjpClientUserAgent(jsonData string) ClientOption {
 	})
 }
 
+// WithFixedUserAgent returns a ClientOption that sets the User-Agent header to ua and drops the
+// X-Payjp-Client-User-Agent header, whose bindings version and runtime information vary between
+// environments, so captured requests are reproducible, e.g. in golden tests.
+// Pass WithXPayjpClientUserAgent after it to pin that header to a fixed value instead.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithFixedUserAgent("golden-test"),
+//	    payjpv2.WithXPayjpClientUserAgent(`{"lang":"go"}`),
+//	)
+func WithFixedUserAgent(ua string) ClientOption {
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("User-Agent", ua)
+		req.Header.Del("X-Payjp-Client-User-Agent")
+		return nil
+	})
+}
+
 // WithAPIKey returns a ClientOption that sets the Authorization header with the API key
 func WithAPIKey(apiKey string) ClientOption {
 	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
@@ -59,16 +84,189 @@ func WithIdempotencyKey(idempotencyKey string) RequestEditorFn {
 	}
 }
 
+// idempotencyKeyContextKey is the context key for ContextWithIdempotencyKey
+type idempotencyKeyContextKey struct{}
+
+// ContextWithIdempotencyKey returns a copy of ctx carrying an idempotency key.
+// POST requests made with the returned context send it as the Idempotency-Key header,
+// unless WithIdempotencyKey is passed to the call, which takes precedence.
+func ContextWithIdempotencyKey(ctx context.Context, idempotencyKey string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, idempotencyKey)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key set by ContextWithIdempotencyKey, if any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	idempotencyKey, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return idempotencyKey, ok && idempotencyKey != ""
+}
+
+// withContextIdempotencyKey returns a ClientOption that sets the Idempotency-Key header
+// on POST requests from the key stored in the request context.
+// Client-level editors run before per-request editors, so WithIdempotencyKey overrides it.
+func withContextIdempotencyKey() ClientOption {
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		if req.Method != http.MethodPost {
+			return nil
+		}
+		if idempotencyKey, ok := IdempotencyKeyFromContext(ctx); ok {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		return nil
+	})
+}
+
+// IdempotencyKeyFunc computes an idempotency key from a request's method, path and body.
+type IdempotencyKeyFunc func(method, path string, body []byte) string
+
+// WithIdempotencyKeyFunc returns a ClientOption that sets the Idempotency-Key header of POST requests
+// to the key computed by fn, unless the request already has one from ContextWithIdempotencyKey.
+// WithIdempotencyKey passed to a call still takes precedence.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithIdempotencyKeyFunc(payjpv2.DeterministicIdempotencyKey),
+//	)
+func WithIdempotencyKeyFunc(fn IdempotencyKeyFunc) ClientOption {
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		if req.Method != http.MethodPost || req.Header.Get("Idempotency-Key") != "" {
+			return nil
+		}
+		var body []byte
+		if req.GetBody != nil {
+			rc, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			body, err = io.ReadAll(rc)
+			_ = rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		if idempotencyKey := fn(req.Method, req.URL.Path, body); idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		return nil
+	})
+}
+
+// DeterministicIdempotencyKey hashes a request's method, path and body into a stable UUID-formatted key,
+// so identical requests get identical keys and retrying one is naturally deduplicated.
+// Different bodies produce different keys. It is an IdempotencyKeyFunc.
+func DeterministicIdempotencyKey(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(strings.ToUpper(method)))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hashUUID(h.Sum(nil))
+}
+
+// SubKey derives the idempotency key of the item at index of a batch from the batch's base key,
+// as a UUID-formatted hash. The same base and index always give the same key and different
+// indexes give different keys, so a whole batch can be retried with the same base key.
+//
+// Example usage:
+//
+//	for i, req := range requests {
+//	    _, err := payjpv2.Extract(client.CreateCustomerWithResponse(ctx, req,
+//	        payjpv2.WithIdempotencyKey(payjpv2.SubKey(batchKey, i)),
+//	    ))
+//	}
+func SubKey(base string, index int) string {
+	h := sha256.New()
+	h.Write([]byte(base))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(index)))
+	return hashUUID(h.Sum(nil))
+}
+
+// hashUUID formats the first 16 bytes of a hash as a name-based (version 5 style) UUID
+func hashUUID(sum []byte) string {
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// traceIDContextKey is the context key for ContextWithTraceID
+type traceIDContextKey struct{}
+
+// ContextWithTraceID returns a copy of ctx carrying a trace id, e.g. set by tracing middleware.
+// Clients created with WithTraceHeaderFromContext send it with every request made with the returned context.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDContextKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace id set by ContextWithTraceID, if any.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	traceID, ok := ctx.Value(traceIDContextKey{}).(string)
+	return traceID, ok && traceID != ""
+}
+
+// WithTraceHeaderFromContext returns a ClientOption that sets the headerName header to the trace id
+// stored in the request context by ContextWithTraceID. The header is omitted when there is no trace id.
+func WithTraceHeaderFromContext(headerName string) ClientOption {
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		if traceID, ok := TraceIDFromContext(ctx); ok {
+			req.Header.Set(headerName, traceID)
+		}
+		return nil
+	})
+}
+
+// ClientRequestIDHeader is the header carrying the client-generated request id set by WithRequestIDGenerator
+const ClientRequestIDHeader = "X-Client-Request-Id"
+
+// WithRequestIDGenerator returns a ClientOption that stamps every request with an id from generate
+// in the ClientRequestIDHeader header, for end-to-end correlation before the server assigns its own.
+// It is independent of idempotency keys. Logging and metrics hooks read it with ClientRequestID.
+// generate is called once per call, so retries of a call share its id. An empty id is not sent.
+func WithRequestIDGenerator(generate func() string) ClientOption {
+	return func(c *Client) error {
+		if generate == nil {
+			return errors.New("request id generator cannot be nil")
+		}
+		return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+			if id := generate(); id != "" {
+				req.Header.Set(ClientRequestIDHeader, id)
+			}
+			return nil
+		})(c)
+	}
+}
+
+// ClientRequestID returns the client-generated request id of req set by WithRequestIDGenerator, if any.
+func ClientRequestID(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	return req.Header.Get(ClientRequestIDHeader)
+}
+
 // NewPayjpClientWithResponses creates a new PAY.JP V2 client with request editor function.
 func NewPayjpClientWithResponses(apiKey string, opts ...ClientOption) (*ClientWithResponses, error) {
 	// Validate API key
+	if err := validateAPIKey(apiKey); err != nil {
+		return nil, err
+	}
+	return newPayjpClient(WithAPIKey(apiKey), append([]ClientOption{withKeyType(keyTypeOf(apiKey))}, opts...)...)
+}
+
+// validateAPIKey checks that apiKey looks like a PAY.JP secret key
+func validateAPIKey(apiKey string) error {
 	if apiKey == "" {
-		return nil, errors.New("API key cannot be empty")
+		return errors.New("API key cannot be empty")
 	}
 	if !strings.HasPrefix(apiKey, "sk_") {
-		return nil, fmt.Errorf("invalid API key format: must start with 'sk_'")
+		return fmt.Errorf("invalid API key format: must start with 'sk_'")
 	}
+	return nil
+}
 
+// newPayjpClient creates a client with the SDK's default options, authenticating with auth.
+func newPayjpClient(auth ClientOption, opts ...ClientOption) (*ClientWithResponses, error) {
 	// Collect system information
 	langVersion := runtime.Version()
 	uname := fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
@@ -92,7 +290,9 @@ func NewPayjpClientWithResponses(apiKey string, opts ...ClientOption) (*ClientWi
 	defaultOpts := []ClientOption{
 		WithUserAgent(fmt.Sprintf("payjp/payjpv2 GoBindings/%s", BINDINGS_VERSION)),
 		WithXPayjpClientUserAgent(string(uaJSON)),
-		WithAPIKey(apiKey),
+		auth,
+		withContextIdempotencyKey(),
+		withRateLimitTracking(),
 	}
 	opts = append(defaultOpts, opts...)
 
@@ -114,19 +314,71 @@ type APIError struct {
 	Body *ErrorResponse
 	// RawBody is the raw response body bytes
 	RawBody []byte
+	// Code is the machine-readable error code of the response, e.g. ErrorCodeCardDeclined. It
+	// is read from a code member of the body, which is not part of the ErrorResponse schema,
+	// or else from the last path segment of the problem type URI. It is often empty, e.g. for
+	// the problem type about:blank.
+	Code ErrorCode
+	// RequestID is the id PAY.JP assigned to the request, from the RequestIDHeader response
+	// header, or empty if the response has none. Quote it when contacting PAY.JP support.
+	RequestID string
 	// Err is the underlying error, if any
 	Err error
 }
 
+// newAPIError returns the APIError for an error response, reading its error code from rawBody
+// or the problem type of body, and its request id from httpResp, which may be nil
+func newAPIError(statusCode int, body *ErrorResponse, rawBody []byte, httpResp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Body:       body,
+		RawBody:    rawBody,
+	}
+	code, _ := e.errorCodes()
+	if code == "" && body != nil {
+		code = problemTypeCode(body.Type)
+	}
+	e.Code = ErrorCode(code)
+	if httpResp != nil {
+		e.RequestID = httpResp.Header.Get(RequestIDHeader)
+	}
+	return e
+}
+
 // Error implements the error interface for APIError.
 func (e *APIError) Error() string {
+	prefix := fmt.Sprintf("PAY.JP API error %d", e.StatusCode)
+	if e.RequestID != "" {
+		prefix += " (" + e.RequestID + ")"
+	}
 	if e.Body != nil {
 		if e.Body.Detail != nil && *e.Body.Detail != "" {
-			return fmt.Sprintf("PAY.JP API error %d: %s - %s", e.StatusCode, e.Body.Title, *e.Body.Detail)
+			return fmt.Sprintf("%s: %s - %s", prefix, e.Body.Title, *e.Body.Detail)
 		}
-		return fmt.Sprintf("PAY.JP API error %d: %s", e.StatusCode, e.Body.Title)
+		return fmt.Sprintf("%s: %s", prefix, e.Body.Title)
+	}
+	if snippet := bodySnippet(e.RawBody); snippet != "" {
+		return fmt.Sprintf("%s: %s", prefix, snippet)
 	}
-	return fmt.Sprintf("PAY.JP API error %d", e.StatusCode)
+	return prefix
+}
+
+// maxBodySnippetLength is the maximum length of the raw body included in APIError messages
+const maxBodySnippetLength = 200
+
+// bodySnippet returns the start of an unstructured error body, with whitespace collapsed,
+// for error messages (e.g. an HTML page from a proxy on a 502)
+func bodySnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) <= maxBodySnippetLength {
+		return snippet
+	}
+	// Cut at a rune boundary
+	cut := maxBodySnippetLength
+	for cut > 0 && !utf8.RuneStart(snippet[cut]) {
+		cut--
+	}
+	return snippet[:cut] + "..."
 }
 
 // Unwrap returns the underlying error.
@@ -149,6 +401,114 @@ func (e *APIError) IsUnprocessableEntity() bool {
 	return e.StatusCode == http.StatusUnprocessableEntity
 }
 
+// IsUnauthorized returns true if the error is a 401 Unauthorized error, e.g. an invalid API key.
+func (e *APIError) IsUnauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+// IsForbidden returns true if the error is a 403 Forbidden error.
+func (e *APIError) IsForbidden() bool {
+	return e.StatusCode == http.StatusForbidden
+}
+
+// IsConflict returns true if the error is a 409 Conflict error.
+func (e *APIError) IsConflict() bool {
+	return e.StatusCode == http.StatusConflict
+}
+
+// IsRateLimited returns true if the error is a 429 Too Many Requests error.
+func (e *APIError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsServerError returns true if the error has a 5xx status code.
+func (e *APIError) IsServerError() bool {
+	return e.StatusCode >= http.StatusInternalServerError
+}
+
+// IsPreconditionFailed returns true if the error is a 412 Precondition Failed error.
+func (e *APIError) IsPreconditionFailed() bool {
+	return e.StatusCode == http.StatusPreconditionFailed
+}
+
+// errorCodes reads the optional code and decline_code fields of the raw error body,
+// which are not part of the ErrorResponse schema
+func (e *APIError) errorCodes() (code, declineCode string) {
+	var fields struct {
+		Code        string `json:"code"`
+		DeclineCode string `json:"decline_code"`
+	}
+	if err := json.Unmarshal(e.RawBody, &fields); err != nil {
+		return "", ""
+	}
+	return fields.Code, fields.DeclineCode
+}
+
+// problemTypeCode returns the last path segment of an absolute problem type URI (e.g.
+// "card_declined" for https://example.com/problems/card_declined), or empty for about:blank and
+// relative or malformed URIs
+func problemTypeCode(problemType string) string {
+	u, err := url.Parse(problemType)
+	if err != nil || u.Scheme == "" || u.Opaque != "" {
+		return ""
+	}
+	code := path.Base(u.Path)
+	if code == "." || code == "/" {
+		return ""
+	}
+	return code
+}
+
+// IsCardDeclined returns true if the error is a card decline, reported with the card_declined
+// error code or a decline code. Ask the customer for another payment method in that case.
+func (e *APIError) IsCardDeclined() bool {
+	code, declineCode := e.errorCodes()
+	return ErrorCode(code) == ErrorCodeCardDeclined || declineCode != ""
+}
+
+// DeclineCode returns the card issuer's reason for a decline (e.g. insufficient_funds), if the
+// error body carries one.
+func (e *APIError) DeclineCode() (string, bool) {
+	_, declineCode := e.errorCodes()
+	return declineCode, declineCode != ""
+}
+
+// ToHTTPResponse returns the status code and a problem+json body reproducing the error, e.g. to
+// forward it from a gateway. The raw body is returned as is when it is JSON; otherwise the body
+// is rebuilt from the parsed fields, or from the status code alone. A missing status code is
+// reported as 500. Serve the body with the Content-Type application/problem+json.
+//
+// Example usage:
+//
+//	var apiErr *payjpv2.APIError
+//	if errors.As(err, &apiErr) {
+//	    status, body := apiErr.ToHTTPResponse()
+//	    w.Header().Set("Content-Type", "application/problem+json")
+//	    w.WriteHeader(status)
+//	    w.Write(body)
+//	}
+func (e *APIError) ToHTTPResponse() (int, []byte) {
+	status := e.StatusCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	if len(e.RawBody) > 0 && json.Valid(e.RawBody) {
+		return status, e.RawBody
+	}
+
+	problem := ErrorResponse{Status: status, Title: http.StatusText(status), Type: "about:blank"}
+	if e.Body != nil {
+		problem = *e.Body
+	} else if snippet := bodySnippet(e.RawBody); snippet != "" {
+		problem.Detail = &snippet
+	}
+	body, err := json.Marshal(problem)
+	if err != nil {
+		return status, nil
+	}
+	return status, body
+}
+
 // ParseAPIError extracts an APIError from a response struct if an error occurred.
 // It checks the response for error fields (BadRequest, NotFound, UnprocessableEntity)
 // and returns an APIError if one is found, or nil if the request was successful.
@@ -170,16 +530,20 @@ func ParseAPIError(resp interface{}) *APIError {
 
 	v := reflect.ValueOf(resp)
 	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
 		v = v.Elem()
 	}
 	if v.Kind() != reflect.Struct {
 		return nil
 	}
 
-	// Get HTTPResponse to extract status code
-	httpRespField := v.FieldByName("HTTPResponse")
+	// Get the status code from the generated Response method, or the HTTPResponse field otherwise
 	var statusCode int
-	if httpRespField.IsValid() && !httpRespField.IsNil() {
+	if r, ok := resp.(Response); ok {
+		statusCode = r.StatusCode()
+	} else if httpRespField := v.FieldByName("HTTPResponse"); httpRespField.IsValid() && !httpRespField.IsNil() {
 		httpResp := httpRespField.Interface().(*http.Response)
 		statusCode = httpResp.StatusCode
 	}
@@ -196,20 +560,14 @@ func ParseAPIError(resp interface{}) *APIError {
 		field := v.FieldByName(ef.FieldName)
 		if field.IsValid() && !field.IsNil() {
 			errResp := field.Interface().(*ErrorResponse)
-			return &APIError{
-				StatusCode: ef.StatusCode,
-				Body:       errResp,
-				RawBody:    rawBody,
-			}
+			return newAPIError(ef.StatusCode, errResp, rawBody, httpResponseOf(resp))
 		}
 	}
 
-	// Check if status code indicates an error but no specific error field was found
+	// Check if status code indicates an error but no specific error field was found, e.g. a
+	// status the spec does not document for the operation
 	if statusCode >= 400 {
-		return &APIError{
-			StatusCode: statusCode,
-			RawBody:    rawBody,
-		}
+		return newAPIError(statusCode, problemOf(rawBody), rawBody, httpResponseOf(resp))
 	}
 
 	return nil
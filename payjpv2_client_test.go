@@ -2,9 +2,15 @@ package payjpv2
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
@@ -180,6 +186,46 @@ func TestClientHeadersIntegration(t *testing.T) {
 	})
 }
 
+func TestWithFixedUserAgent(t *testing.T) {
+	capture := func(t *testing.T, opts ...ClientOption) http.Header {
+		t.Helper()
+		mockTransport := &mockRoundTripper{}
+		opts = append([]ClientOption{WithHTTPClient(&http.Client{Transport: mockTransport})}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_key", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		return mockTransport.capturedHeaders
+	}
+
+	t.Run("pins the User-Agent and drops runtime information", func(t *testing.T) {
+		headers := capture(t, WithFixedUserAgent("golden-test"))
+
+		if got := headers.Get("User-Agent"); got != "golden-test" {
+			t.Errorf("User-Agent header incorrect. Got: %s, Expected: golden-test", got)
+		}
+		if values := headers.Values("X-Payjp-Client-User-Agent"); len(values) != 0 {
+			t.Errorf("Expected no X-Payjp-Client-User-Agent header, got: %v", values)
+		}
+	})
+
+	t.Run("pins every identity header together with WithXPayjpClientUserAgent", func(t *testing.T) {
+		headers := capture(t, WithFixedUserAgent("golden-test"), WithXPayjpClientUserAgent(`{"lang":"go"}`))
+
+		expected := http.Header{
+			"User-Agent":                {"golden-test"},
+			"X-Payjp-Client-User-Agent": {`{"lang":"go"}`},
+			"Authorization":             {"Bearer sk_test_key"},
+		}
+		for name, values := range expected {
+			if got := headers.Values(name); len(got) != 1 || got[0] != values[0] {
+				t.Errorf("%s header incorrect. Got: %v, Expected: %v", name, got, values)
+			}
+		}
+	})
+}
+
 func TestWithIdempotencyKey(t *testing.T) {
 	t.Run("sets Idempotency-Key header for POST request", func(t *testing.T) {
 		mockTransport := &mockRoundTripper{}
@@ -247,6 +293,270 @@ func TestWithIdempotencyKey(t *testing.T) {
 	})
 }
 
+func TestContextWithIdempotencyKey(t *testing.T) {
+	t.Run("uses context key when no option is passed", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		httpClient := &http.Client{Transport: mockTransport}
+
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(httpClient))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		ctx := ContextWithIdempotencyKey(context.Background(), "context-key")
+		email := openapi_types.Email("test@example.com")
+		_, _ = client.CreateCustomerWithResponse(ctx, CreateCustomerJSONRequestBody{Email: &email})
+
+		if got := mockTransport.capturedHeaders.Get("Idempotency-Key"); got != "context-key" {
+			t.Errorf("Idempotency-Key header incorrect. Got: %s, Expected: context-key", got)
+		}
+	})
+
+	t.Run("explicit option overrides context key", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		httpClient := &http.Client{Transport: mockTransport}
+
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(httpClient))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		ctx := ContextWithIdempotencyKey(context.Background(), "context-key")
+		email := openapi_types.Email("test@example.com")
+		_, _ = client.CreateCustomerWithResponse(ctx, CreateCustomerJSONRequestBody{Email: &email}, WithIdempotencyKey("explicit-key"))
+
+		if got := mockTransport.capturedHeaders.Get("Idempotency-Key"); got != "explicit-key" {
+			t.Errorf("Idempotency-Key header incorrect. Got: %s, Expected: explicit-key", got)
+		}
+	})
+
+	t.Run("not sent for GET requests", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		httpClient := &http.Client{Transport: mockTransport}
+
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(httpClient))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		ctx := ContextWithIdempotencyKey(context.Background(), "context-key")
+		_, _ = client.GetCustomerWithResponse(ctx, "cus_123")
+
+		if got := mockTransport.capturedHeaders.Get("Idempotency-Key"); got != "" {
+			t.Errorf("Expected no Idempotency-Key header for GET, got: %s", got)
+		}
+	})
+}
+
+func TestDeterministicIdempotencyKey(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	t.Run("is deterministic", func(t *testing.T) {
+		a := DeterministicIdempotencyKey("POST", "/v2/customers", []byte(`{"email":"a@example.com"}`))
+		b := DeterministicIdempotencyKey("POST", "/v2/customers", []byte(`{"email":"a@example.com"}`))
+		if a != b {
+			t.Errorf("Keys differ for identical requests: %s, %s", a, b)
+		}
+		if !uuidPattern.MatchString(a) {
+			t.Errorf("Key is not UUID formatted: %s", a)
+		}
+	})
+
+	t.Run("differs for different requests", func(t *testing.T) {
+		keys := map[string]bool{
+			DeterministicIdempotencyKey("POST", "/v2/customers", []byte(`{"email":"a@example.com"}`)): true,
+			DeterministicIdempotencyKey("POST", "/v2/customers", []byte(`{"email":"b@example.com"}`)): true,
+			DeterministicIdempotencyKey("POST", "/v2/products", []byte(`{"email":"a@example.com"}`)):  true,
+			DeterministicIdempotencyKey("POST", "/v2/customers", nil):                                 true,
+			// The separators keep path and body from running together
+			DeterministicIdempotencyKey("POST", "/v2/customers{}", nil):        true,
+			DeterministicIdempotencyKey("POST", "/v2/customers", []byte("{}")): true,
+		}
+		if len(keys) != 6 {
+			t.Errorf("Expected 6 distinct keys, got %d", len(keys))
+		}
+	})
+
+	t.Run("is used by WithIdempotencyKeyFunc", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithHTTPClient(&http.Client{Transport: mockTransport}),
+			WithIdempotencyKeyFunc(DeterministicIdempotencyKey),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		description := "VIP"
+		body := CreateCustomerJSONRequestBody{Description: &description}
+		_, _ = client.CreateCustomerWithResponse(context.Background(), body)
+		expected := DeterministicIdempotencyKey("POST", "/v2/customers", []byte(`{"description":"VIP"}`))
+		if got := mockTransport.capturedHeaders.Get("Idempotency-Key"); got != expected {
+			t.Errorf("Idempotency-Key header incorrect. Got: %s, Expected: %s", got, expected)
+		}
+
+		ctx := ContextWithIdempotencyKey(context.Background(), "context-key")
+		_, _ = client.CreateCustomerWithResponse(ctx, body)
+		if got := mockTransport.capturedHeaders.Get("Idempotency-Key"); got != "context-key" {
+			t.Errorf("Idempotency-Key header incorrect. Got: %s, Expected: context-key", got)
+		}
+
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_123")
+		if got := mockTransport.capturedHeaders.Get("Idempotency-Key"); got != "" {
+			t.Errorf("Expected no Idempotency-Key header for GET, got: %s", got)
+		}
+	})
+}
+
+func TestSubKey(t *testing.T) {
+	t.Run("is deterministic", func(t *testing.T) {
+		if a, b := SubKey("batch-2024-01", 3), SubKey("batch-2024-01", 3); a != b {
+			t.Errorf("Keys differ for the same base and index: %s, %s", a, b)
+		}
+		if key := SubKey("batch-2024-01", 3); !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(key) {
+			t.Errorf("Key is not UUID formatted: %s", key)
+		}
+	})
+
+	t.Run("is unique across indexes and bases", func(t *testing.T) {
+		keys := map[string]bool{}
+		for i := 0; i < 1000; i++ {
+			keys[SubKey("batch", i)] = true
+		}
+		// The separator keeps base and index from running together
+		keys[SubKey("batch1", 0)] = true
+		keys[SubKey("batch", -1)] = true
+		if len(keys) != 1002 {
+			t.Errorf("Expected 1002 distinct keys, got %d", len(keys))
+		}
+	})
+}
+
+func TestWithTraceHeaderFromContext(t *testing.T) {
+	t.Run("sets header from context trace id", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		httpClient := &http.Client{Transport: mockTransport}
+
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithHTTPClient(httpClient),
+			WithTraceHeaderFromContext("X-Trace-Id"),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		ctx := ContextWithTraceID(context.Background(), "trace-123")
+		_, _ = client.GetCustomerWithResponse(ctx, "cus_123")
+
+		if got := mockTransport.capturedHeaders.Get("X-Trace-Id"); got != "trace-123" {
+			t.Errorf("X-Trace-Id header incorrect. Got: %s, Expected: trace-123", got)
+		}
+	})
+
+	t.Run("omits header without trace id", func(t *testing.T) {
+		mockTransport := &mockRoundTripper{}
+		httpClient := &http.Client{Transport: mockTransport}
+
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithHTTPClient(httpClient),
+			WithTraceHeaderFromContext("X-Trace-Id"),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_123")
+
+		if _, ok := mockTransport.capturedHeaders["X-Trace-Id"]; ok {
+			t.Errorf("Expected no X-Trace-Id header, got: %s", mockTransport.capturedHeaders.Get("X-Trace-Id"))
+		}
+	})
+}
+
+func TestWithRequestIDGenerator(t *testing.T) {
+	newClient := func(t *testing.T, ids *[]string, status int, generate func() string, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		// A logging hook wrapping the transport
+		loggingTransport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			*ids = append(*ids, ClientRequestID(req))
+			return jsonResponse(status, fmt.Sprintf(`{"status":%d,"title":"%s","type":"about:blank"}`, status, http.StatusText(status))), nil
+		})
+		opts = append([]ClientOption{WithHTTPClient(&http.Client{Transport: loggingTransport}), WithRequestIDGenerator(generate)}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_key", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("stamps each request with a fresh id", func(t *testing.T) {
+		var ids []string
+		n := 0
+		client := newClient(t, &ids, 404, func() string {
+			n++
+			return fmt.Sprintf("creq_%d", n)
+		})
+
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_2")
+
+		if n != 2 {
+			t.Errorf("Generator call count incorrect. Got: %d, Expected: 2", n)
+		}
+		if strings.Join(ids, ",") != "creq_1,creq_2" {
+			t.Errorf("Request ids incorrect. Got: %v, Expected: [creq_1 creq_2]", ids)
+		}
+	})
+
+	t.Run("keeps the id across retries of a call", func(t *testing.T) {
+		var ids []string
+		n := 0
+		client := newClient(t, &ids, 503, func() string {
+			n++
+			return fmt.Sprintf("creq_%d", n)
+		}, WithRetry(2, time.Millisecond))
+
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+
+		if n != 1 {
+			t.Errorf("Generator call count incorrect. Got: %d, Expected: 1", n)
+		}
+		if strings.Join(ids, ",") != "creq_1,creq_1,creq_1" {
+			t.Errorf("Request ids incorrect. Got: %v, Expected: [creq_1 creq_1 creq_1]", ids)
+		}
+	})
+
+	t.Run("omits an empty id", func(t *testing.T) {
+		var ids []string
+		client := newClient(t, &ids, 404, func() string { return "" })
+
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if len(ids) != 1 || ids[0] != "" {
+			t.Errorf("Request ids incorrect. Got: %q, Expected: [\"\"]", ids)
+		}
+	})
+
+	t.Run("ClientRequestID reads the header", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "https://api.pay.jp/v2/customers", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set(ClientRequestIDHeader, "creq_abc")
+		if got := ClientRequestID(req); got != "creq_abc" {
+			t.Errorf("Request id incorrect. Got: %s, Expected: creq_abc", got)
+		}
+		if ClientRequestID(nil) != "" {
+			t.Error("Expected empty request id for nil request")
+		}
+	})
+
+	t.Run("rejects a nil generator", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithRequestIDGenerator(nil)); err == nil {
+			t.Error("Expected error for nil generator, got nil")
+		}
+	})
+}
+
 func TestNewPayjpClientWithResponses_Validation(t *testing.T) {
 	t.Run("rejects empty API key", func(t *testing.T) {
 		_, err := NewPayjpClientWithResponses("")
@@ -323,6 +633,89 @@ func TestAPIError(t *testing.T) {
 		}
 	})
 
+	t.Run("Error() with request id", func(t *testing.T) {
+		apiErr := &APIError{
+			StatusCode: 404,
+			Body:       &ErrorResponse{Title: "Not Found", Status: 404},
+			RequestID:  "req_abc",
+		}
+
+		expected := "PAY.JP API error 404 (req_abc): Not Found"
+		if apiErr.Error() != expected {
+			t.Errorf("Expected error message: %s, got: %s", expected, apiErr.Error())
+		}
+	})
+
+	t.Run("RequestID from the response header", func(t *testing.T) {
+		header := http.Header{"Content-Type": {"application/problem+json"}}
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				resp := jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`)
+				for name, values := range header {
+					resp.Header[name] = values
+				}
+				return resp, nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		header.Set("X-Request-Id", "req_abc")
+		_, err = Extract(client.GetCustomerWithResponse(context.Background(), "cus_123"))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.RequestID != "req_abc" {
+			t.Fatalf("Expected APIError with request id req_abc, got: %v", err)
+		}
+		if expected := "PAY.JP API error 404 (req_abc): Not Found"; err.Error() != expected {
+			t.Errorf("Expected error message: %s, got: %s", expected, err.Error())
+		}
+
+		_, _, err = client.DoRawMap(context.Background(), http.MethodGet, "/v2/customers/cus_123", nil)
+		if !errors.As(err, &apiErr) || apiErr.RequestID != "req_abc" {
+			t.Errorf("Expected raw APIError with request id req_abc, got: %v", err)
+		}
+
+		header.Del("X-Request-Id")
+		_, err = Extract(client.GetCustomerWithResponse(context.Background(), "cus_123"))
+		if !errors.As(err, &apiErr) || apiErr.RequestID != "" {
+			t.Fatalf("Expected APIError without request id, got: %v", err)
+		}
+		if expected := "PAY.JP API error 404: Not Found"; err.Error() != expected {
+			t.Errorf("Expected error message: %s, got: %s", expected, err.Error())
+		}
+	})
+
+	t.Run("Error() with non-JSON body", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 502,
+					Header:     http.Header{"Content-Type": []string{"text/html"}},
+					Body:       io.NopCloser(strings.NewReader("<html>\n  <body>502 Bad Gateway</body>\n</html>" + strings.Repeat(" padding", 50))),
+				}, nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, err = Extract(client.GetCustomerWithResponse(context.Background(), "cus_123"))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got: %v", err)
+		}
+		if apiErr.Body != nil || !strings.HasPrefix(string(apiErr.RawBody), "<html>") {
+			t.Errorf("Unexpected body. Body: %v, RawBody: %s", apiErr.Body, apiErr.RawBody)
+		}
+		if !strings.HasPrefix(apiErr.Error(), "PAY.JP API error 502: <html> <body>502 Bad Gateway</body> </html>") {
+			t.Errorf("Error message missing body snippet: %s", apiErr.Error())
+		}
+		if !strings.HasSuffix(apiErr.Error(), "...") || len(apiErr.Error()) > 240 {
+			t.Errorf("Expected truncated snippet, got: %s", apiErr.Error())
+		}
+	})
+
 	t.Run("IsNotFound", func(t *testing.T) {
 		apiErr := &APIError{StatusCode: 404}
 		if !apiErr.IsNotFound() {
@@ -358,6 +751,123 @@ func TestAPIError(t *testing.T) {
 			t.Error("Expected IsUnprocessableEntity() to return false for 400 status")
 		}
 	})
+
+	t.Run("status predicates", func(t *testing.T) {
+		predicates := map[string]func(*APIError) bool{
+			"IsUnauthorized":       (*APIError).IsUnauthorized,
+			"IsForbidden":          (*APIError).IsForbidden,
+			"IsConflict":           (*APIError).IsConflict,
+			"IsRateLimited":        (*APIError).IsRateLimited,
+			"IsServerError":        (*APIError).IsServerError,
+			"IsPreconditionFailed": (*APIError).IsPreconditionFailed,
+		}
+		tests := []struct {
+			statusCode int
+			expected   string
+		}{
+			{400, ""},
+			{401, "IsUnauthorized"},
+			{403, "IsForbidden"},
+			{404, ""},
+			{409, "IsConflict"},
+			{412, "IsPreconditionFailed"},
+			{429, "IsRateLimited"},
+			{499, ""},
+			{500, "IsServerError"},
+			{503, "IsServerError"},
+		}
+
+		for _, tt := range tests {
+			apiErr := &APIError{StatusCode: tt.statusCode}
+			for name, predicate := range predicates {
+				if got := predicate(apiErr); got != (name == tt.expected) {
+					t.Errorf("%s() incorrect for %d status. Got: %v, Expected: %v", name, tt.statusCode, got, name == tt.expected)
+				}
+			}
+		}
+	})
+
+	t.Run("IsCardDeclined and DeclineCode", func(t *testing.T) {
+		declined := &APIError{
+			StatusCode: 402,
+			RawBody:    []byte(`{"type":"about:blank","title":"Card Declined","status":402,"code":"card_declined","decline_code":"insufficient_funds"}`),
+		}
+		if !declined.IsCardDeclined() {
+			t.Error("Expected IsCardDeclined() to return true for a card_declined error")
+		}
+		if code, ok := declined.DeclineCode(); !ok || code != "insufficient_funds" {
+			t.Errorf("DeclineCode incorrect. Got: %s, %v, Expected: insufficient_funds, true", code, ok)
+		}
+
+		withoutDeclineCode := &APIError{
+			StatusCode: 400,
+			RawBody:    []byte(`{"type":"about:blank","title":"Bad Request","status":400,"code":"card_declined"}`),
+		}
+		if !withoutDeclineCode.IsCardDeclined() {
+			t.Error("Expected IsCardDeclined() to return true without a decline code")
+		}
+		if _, ok := withoutDeclineCode.DeclineCode(); ok {
+			t.Error("Expected no decline code")
+		}
+
+		for _, body := range []string{
+			`{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_error"}`,
+			`<html>502 Bad Gateway</html>`,
+			``,
+		} {
+			apiErr := &APIError{StatusCode: 400, RawBody: []byte(body)}
+			if apiErr.IsCardDeclined() {
+				t.Errorf("Expected IsCardDeclined() to return false for body: %s", body)
+			}
+		}
+	})
+}
+
+func TestAPIErrorToHTTPResponse(t *testing.T) {
+	t.Run("round-trips the raw body", func(t *testing.T) {
+		raw := `{"status":402,"title":"Payment Required","type":"about:blank","code":"card_declined"}`
+		resp := &http.Response{StatusCode: 402, Body: io.NopCloser(strings.NewReader(raw))}
+		status, body := apiErrorFromHTTPResponse(resp, []byte(raw)).ToHTTPResponse()
+		if status != 402 {
+			t.Errorf("Status incorrect. Got: %d, Expected: 402", status)
+		}
+
+		roundTrip := apiErrorFromHTTPResponse(&http.Response{StatusCode: status}, body)
+		if roundTrip.Body == nil || roundTrip.Body.Title != "Payment Required" {
+			t.Errorf("Title incorrect. Got: %+v, Expected: Payment Required", roundTrip.Body)
+		}
+		if !roundTrip.IsCardDeclined() {
+			t.Error("Expected the error code to be preserved")
+		}
+	})
+
+	t.Run("reconstructs the body from the fields", func(t *testing.T) {
+		detail := "Customer not found"
+		apiErr := &APIError{StatusCode: 404, Body: &ErrorResponse{Status: 404, Title: "Not Found", Type: "about:blank", Detail: &detail}}
+		status, body := apiErr.ToHTTPResponse()
+		var problem ErrorResponse
+		if err := json.Unmarshal(body, &problem); err != nil {
+			t.Fatalf("Failed to decode body %s: %v", body, err)
+		}
+		if status != 404 || problem.Title != "Not Found" || problem.Detail == nil || *problem.Detail != detail {
+			t.Errorf("Response incorrect. Got: %d %s", status, body)
+		}
+	})
+
+	t.Run("wraps a non-JSON body", func(t *testing.T) {
+		apiErr := &APIError{StatusCode: 502, RawBody: []byte("<html>Bad Gateway</html>")}
+		status, body := apiErr.ToHTTPResponse()
+		var problem ErrorResponse
+		if err := json.Unmarshal(body, &problem); err != nil {
+			t.Fatalf("Failed to decode body %s: %v", body, err)
+		}
+		if status != 502 || problem.Status != 502 || problem.Title != "Bad Gateway" {
+			t.Errorf("Response incorrect. Got: %d %s", status, body)
+		}
+		if problem.Detail == nil || *problem.Detail != "<html>Bad Gateway</html>" {
+			t.Errorf("Detail incorrect. Got: %v", problem.Detail)
+		}
+	})
 }
 
 func TestParseAPIError(t *testing.T) {
@@ -419,6 +929,21 @@ func TestParseAPIError(t *testing.T) {
 		}
 	})
 
+	t.Run("reads the problem body of an undeclared status", func(t *testing.T) {
+		resp := &UpdateCustomerResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusPreconditionFailed},
+			Body:         []byte(`{"status":412,"title":"Precondition Failed","type":"about:blank","detail":"customer has been modified"}`),
+		}
+
+		apiErr := ParseAPIError(resp)
+		if apiErr == nil || !apiErr.IsPreconditionFailed() {
+			t.Fatalf("Expected precondition failed APIError, got: %v", apiErr)
+		}
+		if apiErr.Body == nil || apiErr.Body.Detail == nil || *apiErr.Body.Detail != "customer has been modified" {
+			t.Errorf("Body incorrect. Got: %+v", apiErr.Body)
+		}
+	})
+
 	t.Run("returns nil for successful response", func(t *testing.T) {
 		resp := &GetCustomerResponse{
 			HTTPResponse: &http.Response{StatusCode: 200},
@@ -430,4 +955,4 @@ func TestParseAPIError(t *testing.T) {
 			t.Errorf("Expected nil for successful response, got: %v", apiErr)
 		}
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,168 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"time"
+)
+
+// settlementPollInterval is how often WaitForPaymentFlowSettlement polls a processing payment flow
+const settlementPollInterval = time.Second
+
+// ErrSettlementTimeout is returned by WaitForPaymentFlowSettlement when the payment flow is
+// still processing after the timeout.
+var ErrSettlementTimeout = errors.New("payment flow is still processing")
+
+// PaymentFlowCreateOption sets optional fields of a request built by NewPaymentFlowCreateRequest.
+type PaymentFlowCreateOption func(*PaymentFlowCreateRequest) error
+
+// NewPaymentFlowCreateRequest builds a PaymentFlowCreateRequest for amount in currency,
+// applying opts in order.
+//
+// Example usage:
+//
+//	req, err := payjpv2.NewPaymentFlowCreateRequest(1000, payjpv2.CurrencyJpy,
+//	    payjpv2.WithReturnURL("https://example.com/checkout/complete"),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	resp, err := payjpv2.Extract(client.CreatePaymentFlowWithResponse(ctx, req))
+func NewPaymentFlowCreateRequest(amount int, currency Currency, opts ...PaymentFlowCreateOption) (PaymentFlowCreateRequest, error) {
+	req := PaymentFlowCreateRequest{Amount: amount, Currency: currency}
+	for _, opt := range opts {
+		if err := opt(&req); err != nil {
+			return PaymentFlowCreateRequest{}, err
+		}
+	}
+	return req, nil
+}
+
+// WithReturnURL returns a PaymentFlowCreateOption that sets the URL the customer is redirected to
+// after completing or canceling a payment that requires action, such as 3-D Secure. The API only
+// accepts return_url together with confirm, so it also sets confirm to true. rawURL must be an
+// absolute http(s) URL or use an app's URI scheme (e.g. myapp://payment/complete).
+func WithReturnURL(rawURL string) PaymentFlowCreateOption {
+	return func(req *PaymentFlowCreateRequest) error {
+		if err := validateRedirectURL("return URL", rawURL); err != nil {
+			return err
+		}
+		confirm := true
+		req.ReturnUrl = &rawURL
+		req.Confirm = &confirm
+		return nil
+	}
+}
+
+// validateRedirectURL checks that rawURL is usable as a redirect target; name describes the
+// URL in errors, e.g. "return URL"
+func validateRedirectURL(name, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, rawURL, err)
+	}
+	switch u.Scheme {
+	case "":
+		return fmt.Errorf("invalid %s %q: must be absolute", name, rawURL)
+	case "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("invalid %s %q: missing host", name, rawURL)
+		}
+	case "javascript", "data", "file":
+		return fmt.Errorf("invalid %s %q: unsupported scheme %s", name, rawURL, u.Scheme)
+	}
+	return nil
+}
+
+// AllCustomerPaymentFlows returns an iterator over every payment flow of a customer, fetching
+// further pages as needed. Payment flows are the charges of the v2 API. Iteration stops at the
+// first error, including cancellation of ctx.
+//
+// Example usage:
+//
+//	for flow, err := range client.AllCustomerPaymentFlows(ctx, "cus_xxx") {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(flow.Id, flow.Amount, flow.Status)
+//	}
+func (c *ClientWithResponses) AllCustomerPaymentFlows(ctx context.Context, customerID string, reqEditors ...RequestEditorFn) iter.Seq2[*PaymentFlowResponse, error] {
+	limit := listPageLimit
+	return paginate(ctx, c.maxPagesOf(), func(startingAfter *string) ([]PaymentFlowResponse, bool, error) {
+		params := &GetAllPaymentFlowsParams{Limit: &limit, StartingAfter: startingAfter, CustomerId: &customerID}
+		resp, err := Extract(c.GetAllPaymentFlowsWithResponse(ctx, params, reqEditors...))
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Result == nil {
+			return nil, false, errors.New("list payment flows response has no result")
+		}
+		return resp.Result.Data, resp.Result.HasMore, nil
+	}, func(f *PaymentFlowResponse) string { return f.Id })
+}
+
+// WaitForPaymentFlowSettlement polls a payment flow until it is no longer in the processing state,
+// and returns it. A processing payment flow has not failed yet; it settles to succeeded, or back to
+// requires_payment_method if the payment failed. When timeout elapses first, the last fetched
+// payment flow is returned with ErrSettlementTimeout. Time is measured with the client's Clock.
+//
+// Example usage:
+//
+//	flow, err := client.WaitForPaymentFlowSettlement(ctx, "pfw_xxx", 30*time.Second)
+//	if errors.Is(err, payjpv2.ErrSettlementTimeout) {
+//	    // still processing; check again later or wait for the webhook
+//	}
+func (c *ClientWithResponses) WaitForPaymentFlowSettlement(ctx context.Context, paymentFlowID string, timeout time.Duration, reqEditors ...RequestEditorFn) (*PaymentFlowResponse, error) {
+	clock := c.clockOf()
+	deadline := clock.Now().Add(timeout)
+	for {
+		resp, err := Extract(c.GetPaymentFlowWithResponse(ctx, paymentFlowID, reqEditors...))
+		if err != nil {
+			return nil, err
+		}
+		if resp.Result == nil {
+			return nil, errors.New("get payment flow response has no result")
+		}
+		if resp.Result.Status != PaymentFlowStatusProcessing {
+			return resp.Result, nil
+		}
+
+		remaining := deadline.Sub(clock.Now())
+		if remaining <= 0 {
+			return resp.Result, ErrSettlementTimeout
+		}
+		if err := sleep(ctx, clock, min(settlementPollInterval, remaining)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// BelongsTo reports whether the payment flow is associated with the customer customerID, e.g. to
+// check that a payment flow may be shown to the authenticated customer. It returns false for a
+// payment flow without a customer, a nil payment flow and an empty customerID.
+//
+// Example usage:
+//
+//	if !flow.BelongsTo(session.CustomerID) {
+//	    http.NotFound(w, r)
+//	    return
+//	}
+func (f *PaymentFlowResponse) BelongsTo(customerID string) bool {
+	return f != nil && customerID != "" && f.CustomerId != nil && *f.CustomerId == customerID
+}
+
+// IsPaid reports whether the payment has been authorized, i.e. the payment flow is awaiting
+// capture or has succeeded. It corresponds to the paid flag of v1 charges, which the v2 API
+// expresses with the status. A nil payment flow is not paid.
+func (f *PaymentFlowResponse) IsPaid() bool {
+	return f != nil && (f.Status == PaymentFlowStatusRequiresCapture || f.Status == PaymentFlowStatusSucceeded)
+}
+
+// IsCaptured reports whether the funds have been captured, i.e. the payment flow has succeeded.
+// It corresponds to the captured flag of v1 charges. A nil payment flow is not captured.
+func (f *PaymentFlowResponse) IsCaptured() bool {
+	return f != nil && f.Status == PaymentFlowStatusSucceeded
+}
@@ -0,0 +1,192 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAllCustomerPaymentFlows(t *testing.T) {
+	flow := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"payment_flow","amount":1000,"currency":"jpy","customer_id":"cus_1","status":"succeeded","livemode":false,"metadata":{}}`, id)
+	}
+	pages := map[string]string{
+		"":      `{"object":"list","url":"/v2/payment_flows","has_more":true,"data":[` + flow("pfw_1") + "," + flow("pfw_2") + `]}`,
+		"pfw_2": `{"object":"list","url":"/v2/payment_flows","has_more":false,"data":[` + flow("pfw_3") + `]}`,
+	}
+	var queries []string
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			queries = append(queries, req.URL.RawQuery)
+			return jsonResponse(200, pages[req.URL.Query().Get("starting_after")]), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var ids []string
+	for flow, err := range client.AllCustomerPaymentFlows(context.Background(), "cus_1") {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, flow.Id)
+	}
+	if strings.Join(ids, ",") != "pfw_1,pfw_2,pfw_3" {
+		t.Errorf("Payment flows incorrect. Got: %v, Expected: [pfw_1 pfw_2 pfw_3]", ids)
+	}
+	expectedQueries := []string{
+		"customer_id=cus_1&limit=100",
+		"customer_id=cus_1&limit=100&starting_after=pfw_2",
+	}
+	if strings.Join(queries, " ") != strings.Join(expectedQueries, " ") {
+		t.Errorf("Queries incorrect. Got: %v, Expected: %v", queries, expectedQueries)
+	}
+}
+
+func TestWaitForPaymentFlowSettlement(t *testing.T) {
+	flowWithStatus := func(status string) string {
+		return fmt.Sprintf(`{"id":"pfw_1","object":"payment_flow","amount":1000,"currency":"jpy","status":%q,"livemode":false,"metadata":{}}`, status)
+	}
+	newClient := func(t *testing.T, clock Clock, statuses ...string) (*ClientWithResponses, *int) {
+		t.Helper()
+		polls := 0
+		client, err := NewPayjpClientWithResponses("sk_test_example",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != "/v2/payment_flows/pfw_1" {
+					t.Errorf("Path incorrect. Got: %s, Expected: /v2/payment_flows/pfw_1", req.URL.Path)
+				}
+				status := statuses[min(polls, len(statuses)-1)]
+				polls++
+				return jsonResponse(200, flowWithStatus(status)), nil
+			})}),
+			WithClock(clock),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client, &polls
+	}
+
+	t.Run("returns once the payment flow settles", func(t *testing.T) {
+		clock := newFakeClock()
+		client, polls := newClient(t, clock, "processing", "succeeded")
+
+		flow, err := client.WaitForPaymentFlowSettlement(context.Background(), "pfw_1", time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if flow.Status != PaymentFlowStatusSucceeded {
+			t.Errorf("Status incorrect. Got: %s, Expected: succeeded", flow.Status)
+		}
+		if *polls != 2 {
+			t.Errorf("Poll count incorrect. Got: %d, Expected: 2", *polls)
+		}
+		if len(clock.sleeps) != 1 || clock.sleeps[0] != settlementPollInterval {
+			t.Errorf("Sleeps incorrect. Got: %v, Expected: [%s]", clock.sleeps, settlementPollInterval)
+		}
+	})
+
+	t.Run("times out while processing", func(t *testing.T) {
+		clock := newFakeClock()
+		client, polls := newClient(t, clock, "processing")
+
+		flow, err := client.WaitForPaymentFlowSettlement(context.Background(), "pfw_1", 2500*time.Millisecond)
+		if !errors.Is(err, ErrSettlementTimeout) {
+			t.Fatalf("Expected ErrSettlementTimeout, got: %v", err)
+		}
+		if flow == nil || flow.Status != PaymentFlowStatusProcessing {
+			t.Errorf("Expected the last processing payment flow, got: %+v", flow)
+		}
+		if *polls != 4 {
+			t.Errorf("Poll count incorrect. Got: %d, Expected: 4", *polls)
+		}
+	})
+}
+
+func TestNewPaymentFlowCreateRequest(t *testing.T) {
+	t.Run("sets the return URL and confirms", func(t *testing.T) {
+		req, err := NewPaymentFlowCreateRequest(1000, CurrencyJpy, WithReturnURL("https://example.com/complete?order=1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"amount":1000,"confirm":true,"currency":"jpy","return_url":"https://example.com/complete?order=1"}`
+		if string(body) != expected {
+			t.Errorf("Body incorrect. Got: %s, Expected: %s", body, expected)
+		}
+	})
+
+	t.Run("accepts app URI schemes", func(t *testing.T) {
+		req, err := NewPaymentFlowCreateRequest(1000, CurrencyJpy, WithReturnURL("myapp://payment/complete"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.ReturnUrl == nil || *req.ReturnUrl != "myapp://payment/complete" {
+			t.Errorf("ReturnUrl incorrect. Got: %v", req.ReturnUrl)
+		}
+	})
+
+	t.Run("rejects invalid return URLs", func(t *testing.T) {
+		for _, rawURL := range []string{"", "/complete", "https:///complete", "javascript:alert(1)", "://bad"} {
+			if _, err := NewPaymentFlowCreateRequest(1000, CurrencyJpy, WithReturnURL(rawURL)); err == nil {
+				t.Errorf("Expected error for %q, got nil", rawURL)
+			}
+		}
+	})
+}
+
+func TestPaymentFlowBelongsTo(t *testing.T) {
+	customerID := "cus_1"
+	tests := []struct {
+		name       string
+		flow       *PaymentFlowResponse
+		customerID string
+		expected   bool
+	}{
+		{"matching customer", &PaymentFlowResponse{CustomerId: &customerID}, "cus_1", true},
+		{"other customer", &PaymentFlowResponse{CustomerId: &customerID}, "cus_2", false},
+		{"no customer", &PaymentFlowResponse{}, "cus_1", false},
+		{"empty customer ID", &PaymentFlowResponse{}, "", false},
+		{"nil payment flow", nil, "cus_1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flow.BelongsTo(tt.customerID); got != tt.expected {
+				t.Errorf("BelongsTo(%q) incorrect. Got: %v, Expected: %v", tt.customerID, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPaymentFlowIsPaidAndIsCaptured(t *testing.T) {
+	tests := []struct {
+		name           string
+		flow           *PaymentFlowResponse
+		paid, captured bool
+	}{
+		{"authorized and uncaptured", &PaymentFlowResponse{Status: PaymentFlowStatusRequiresCapture, CaptureMethod: CaptureMethodManual}, true, false},
+		{"fully paid", &PaymentFlowResponse{Status: PaymentFlowStatusSucceeded}, true, true},
+		{"awaiting 3-D Secure", &PaymentFlowResponse{Status: PaymentFlowStatusRequiresAction}, false, false},
+		{"canceled", &PaymentFlowResponse{Status: PaymentFlowStatusCanceled}, false, false},
+		{"nil payment flow", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flow.IsPaid(); got != tt.paid {
+				t.Errorf("IsPaid incorrect. Got: %v, Expected: %v", got, tt.paid)
+			}
+			if got := tt.flow.IsCaptured(); got != tt.captured {
+				t.Errorf("IsCaptured incorrect. Got: %v, Expected: %v", got, tt.captured)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package payjpv2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+)
+
+// paymentMethodHeader reads the fields shared by every payment method variant
+func paymentMethodHeader(pm *PaymentMethodResponse) (id, typ string) {
+	var header struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if data, err := pm.MarshalJSON(); err == nil {
+		_ = json.Unmarshal(data, &header)
+	}
+	return header.ID, header.Type
+}
+
+// ListCustomerPaymentMethods returns an iterator over the payment methods saved for a customer,
+// fetching pages as needed. If typeFilter is not empty, only payment methods of that type
+// (e.g. "card") are yielded; the endpoint has no type parameter, so the filter is applied to
+// each page. Iteration stops at the first error, including cancellation of ctx.
+//
+// Example usage:
+//
+//	for pm, err := range client.ListCustomerPaymentMethods(ctx, customerID, "card") {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    card, err := pm.AsPaymentMethodCardResponse()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(card.Card.Brand, card.Card.Last4)
+//	}
+func (c *ClientWithResponses) ListCustomerPaymentMethods(ctx context.Context, customerID string, typeFilter string, reqEditors ...RequestEditorFn) iter.Seq2[*PaymentMethodResponse, error] {
+	limit := listPageLimit
+	paymentMethods := paginate(ctx, c.maxPagesOf(), func(startingAfter *string) ([]PaymentMethodResponse, bool, error) {
+		params := &GetCustomerPaymentMethodsParams{Limit: &limit, StartingAfter: startingAfter}
+		resp, err := Extract(c.GetCustomerPaymentMethodsWithResponse(ctx, customerID, params, reqEditors...))
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Result == nil {
+			return nil, false, errors.New("list customer payment methods response has no result")
+		}
+		return resp.Result.Data, resp.Result.HasMore, nil
+	}, func(pm *PaymentMethodResponse) string {
+		id, _ := paymentMethodHeader(pm)
+		return id
+	})
+
+	return func(yield func(*PaymentMethodResponse, error) bool) {
+		for pm, err := range paymentMethods {
+			if err == nil && typeFilter != "" {
+				if _, typ := paymentMethodHeader(pm); typ != typeFilter {
+					continue
+				}
+			}
+			if !yield(pm, err) {
+				return
+			}
+		}
+	}
+}
+
+// UpdatePaymentMethodBilling updates only the billing details of a card payment method. Like
+// UpdateCustomerMetadata, the request body contains just the payment method type and
+// billing_details, so the metadata is left untouched. Fields of billing left nil are not sent.
+//
+// Example usage:
+//
+//	email := "hanako@example.com"
+//	pm, err := client.UpdatePaymentMethodBilling(ctx, "pm_xxx", payjpv2.PaymentMethodCardBillingDetailsRequest{
+//	    Email: &email,
+//	})
+func (c *ClientWithResponses) UpdatePaymentMethodBilling(ctx context.Context, pmID string, billing PaymentMethodCardBillingDetailsRequest, reqEditors ...RequestEditorFn) (*PaymentMethodResponse, error) {
+	if billing.IsZero() {
+		return nil, errors.New("billing details cannot be empty")
+	}
+	body, err := json.Marshal(struct {
+		Type           PaymentMethodTypes                     `json:"type"`
+		BillingDetails PaymentMethodCardBillingDetailsRequest `json:"billing_details"`
+	}{PaymentMethodTypesCard, billing})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := Extract(c.UpdatePaymentMethodWithBodyWithResponse(ctx, pmID, "application/json", bytes.NewReader(body), reqEditors...))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, errors.New("update payment method response has no result")
+	}
+	return resp.Result, nil
+}
+
+// paymentMethodCardBrand reads the card brand of a card payment method. The type is checked
+// on the raw JSON, as the generated union has no discriminator accessor.
+func paymentMethodCardBrand(pm *PaymentMethodResponse) *string {
+	var fields struct {
+		Type string `json:"type"`
+		Card *struct {
+			Brand *string `json:"brand"`
+		} `json:"card"`
+	}
+	if pm == nil {
+		return nil
+	}
+	data, err := pm.MarshalJSON()
+	if err != nil || json.Unmarshal(data, &fields) != nil {
+		return nil
+	}
+	if fields.Type != string(PaymentMethodTypesCard) || fields.Card == nil {
+		return nil
+	}
+	return fields.Card.Brand
+}
+
+// CardBrand returns the brand of a card payment method (e.g. "Visa", "Mastercard").
+// It returns false for other payment method types.
+//
+// Example usage:
+//
+//	if brand, ok := pm.CardBrand(); ok && brand == "American Express" {
+//	    // apply Amex specific rules
+//	}
+func (pm *PaymentMethodResponse) CardBrand() (string, bool) {
+	brand := paymentMethodCardBrand(pm)
+	if brand == nil || *brand == "" {
+		return "", false
+	}
+	return *brand, true
+}
@@ -0,0 +1,138 @@
+package payjpv2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestListCustomerPaymentMethods(t *testing.T) {
+	card := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"payment_method","type":"card","livemode":false,"customer_id":"cus_1","card":{"brand":"Visa","exp_month":12,"exp_year":2030,"fingerprint":"fp","last4":"4242"},"billing_details":{},"metadata":{},"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`, id)
+	}
+	paypay := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"payment_method","type":"paypay","livemode":false,"customer_id":"cus_1","billing_details":{},"metadata":{},"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`, id)
+	}
+	pages := map[string]string{
+		"":     `{"object":"list","url":"/v2/customers/cus_1/payment_methods","has_more":true,"data":[` + card("pm_1") + "," + paypay("pm_2") + `]}`,
+		"pm_2": `{"object":"list","url":"/v2/customers/cus_1/payment_methods","has_more":false,"data":[` + paypay("pm_3") + "," + card("pm_4") + `]}`,
+	}
+	var paths []string
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			paths = append(paths, req.URL.Path+"?starting_after="+req.URL.Query().Get("starting_after"))
+			return jsonResponse(200, pages[req.URL.Query().Get("starting_after")]), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	collect := func(t *testing.T, typeFilter string) []string {
+		t.Helper()
+		paths = nil
+		var ids []string
+		for pm, err := range client.ListCustomerPaymentMethods(context.Background(), "cus_1", typeFilter) {
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			id, _ := paymentMethodHeader(pm)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	t.Run("filters to cards across two pages", func(t *testing.T) {
+		ids := collect(t, "card")
+		if strings.Join(ids, ",") != "pm_1,pm_4" {
+			t.Errorf("Payment methods incorrect. Got: %v, Expected: [pm_1 pm_4]", ids)
+		}
+		expectedPaths := "/v2/customers/cus_1/payment_methods?starting_after=,/v2/customers/cus_1/payment_methods?starting_after=pm_2"
+		if strings.Join(paths, ",") != expectedPaths {
+			t.Errorf("Requests incorrect. Got: %v", paths)
+		}
+	})
+
+	t.Run("yields every type without a filter", func(t *testing.T) {
+		ids := collect(t, "")
+		if strings.Join(ids, ",") != "pm_1,pm_2,pm_3,pm_4" {
+			t.Errorf("Payment methods incorrect. Got: %v, Expected: [pm_1 pm_2 pm_3 pm_4]", ids)
+		}
+	})
+}
+
+func TestPaymentMethodCardAccessors(t *testing.T) {
+	decode := func(t *testing.T, body string) *PaymentMethodResponse {
+		t.Helper()
+		var pm PaymentMethodResponse
+		if err := pm.UnmarshalJSON([]byte(body)); err != nil {
+			t.Fatalf("Failed to decode payment method: %v", err)
+		}
+		return &pm
+	}
+
+	t.Run("reads the brand of a card", func(t *testing.T) {
+		pm := decode(t, `{"id":"pm_1","object":"payment_method","type":"card","card":{"brand":"Visa","country":"JP","exp_month":12,"exp_year":2030,"fingerprint":"fp","last4":"4242"},"billing_details":{},"metadata":{}}`)
+		if brand, ok := pm.CardBrand(); !ok || brand != "Visa" {
+			t.Errorf("CardBrand incorrect. Got: %q, %v, Expected: Visa, true", brand, ok)
+		}
+	})
+
+	t.Run("returns false for other payment methods", func(t *testing.T) {
+		pm := decode(t, `{"id":"pm_2","object":"payment_method","type":"paypay","billing_details":{},"metadata":{}}`)
+		if brand, ok := pm.CardBrand(); ok {
+			t.Errorf("Expected no brand, got: %q", brand)
+		}
+	})
+
+	t.Run("returns false for nil", func(t *testing.T) {
+		var pm *PaymentMethodResponse
+		if _, ok := pm.CardBrand(); ok {
+			t.Error("Expected no brand for nil")
+		}
+	})
+}
+
+func TestUpdatePaymentMethodBilling(t *testing.T) {
+	var body string
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodPost || req.URL.Path != "/v2/payment_methods/pm_1" {
+				t.Errorf("Request incorrect. Got: %s %s, Expected: POST /v2/payment_methods/pm_1", req.Method, req.URL.Path)
+			}
+			data, _ := io.ReadAll(req.Body)
+			body = string(data)
+			return jsonResponse(200, `{"id":"pm_1","object":"payment_method","type":"card","livemode":false,"card":{"brand":"Visa","exp_month":12,"exp_year":2030,"fingerprint":"fp","last4":"4242"},"billing_details":{"email":"hanako@example.com"},"metadata":{"plan":"premium"},"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z"}`), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("sends only the billing details", func(t *testing.T) {
+		email := "hanako@example.com"
+		zip := "1500001"
+		pm, err := client.UpdatePaymentMethodBilling(context.Background(), "pm_1", PaymentMethodCardBillingDetailsRequest{
+			Email:   &email,
+			Address: &PaymentMethodBillingAddressRequest{Zip: &zip},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := `{"type":"card","billing_details":{"address":{"zip":"1500001"},"email":"hanako@example.com"}}`; body != expected {
+			t.Errorf("Body incorrect. Got: %s, Expected: %s", body, expected)
+		}
+		if id, _ := paymentMethodHeader(pm); id != "pm_1" {
+			t.Errorf("Id incorrect. Got: %s, Expected: pm_1", id)
+		}
+	})
+
+	t.Run("requires billing details", func(t *testing.T) {
+		if _, err := client.UpdatePaymentMethodBilling(context.Background(), "pm_1", PaymentMethodCardBillingDetailsRequest{}); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
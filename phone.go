@@ -0,0 +1,76 @@
+package payjpv2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// phoneRegion is the numbering plan of a region supported by NormalizePhone
+type phoneRegion struct {
+	countryCode string
+	trunkPrefix string
+	// minDigits and maxDigits bound the national significant number (without the trunk prefix)
+	minDigits, maxDigits int
+}
+
+// phoneRegions are the regions NormalizePhone can convert domestic numbers for, by ISO 3166-1 code
+var phoneRegions = map[string]phoneRegion{
+	"JP": {countryCode: "81", trunkPrefix: "0", minDigits: 9, maxDigits: 10},
+	"US": {countryCode: "1", minDigits: 10, maxDigits: 10},
+}
+
+// NormalizePhone converts a phone number to E.164 (e.g. +819012345678), as expected for the phone
+// of billing details. Spaces, hyphens, dots and parentheses are ignored. A number without a leading
+// + is read as a domestic number of defaultRegion, an ISO 3166-1 code; JP and US are supported.
+// Input that cannot be a phone number is rejected without calling the API.
+//
+// Example usage:
+//
+//	phone, err := payjpv2.NormalizePhone("090-1234-5678", "JP")
+//	if err != nil {
+//	    return err
+//	}
+//	details := payjpv2.PaymentMethodCardBillingDetailsRequest{Phone: &phone}
+func NormalizePhone(raw, defaultRegion string) (string, error) {
+	number := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '　':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+
+	international := strings.HasPrefix(number, "+")
+	digits := strings.TrimPrefix(number, "+")
+	if digits == "" {
+		return "", fmt.Errorf("invalid phone number %q: no digits", raw)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("invalid phone number %q: unexpected character %q", raw, r)
+		}
+	}
+
+	if !international {
+		region, ok := phoneRegions[strings.ToUpper(defaultRegion)]
+		if !ok {
+			return "", fmt.Errorf("invalid phone number %q: unsupported region %q for a domestic number", raw, defaultRegion)
+		}
+		if region.trunkPrefix != "" {
+			if !strings.HasPrefix(digits, region.trunkPrefix) {
+				return "", fmt.Errorf("invalid phone number %q: domestic numbers start with %s", raw, region.trunkPrefix)
+			}
+			digits = strings.TrimPrefix(digits, region.trunkPrefix)
+		}
+		if len(digits) < region.minDigits || len(digits) > region.maxDigits {
+			return "", fmt.Errorf("invalid phone number %q: wrong number of digits", raw)
+		}
+		digits = region.countryCode + digits
+	}
+
+	// E.164 numbers have at most 15 digits and country codes do not start with 0
+	if digits[0] == '0' || len(digits) < 8 || len(digits) > 15 {
+		return "", fmt.Errorf("invalid phone number %q: not a valid E.164 number", raw)
+	}
+	return "+" + digits, nil
+}
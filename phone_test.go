@@ -0,0 +1,51 @@
+package payjpv2
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		region   string
+		expected string
+	}{
+		{"japanese mobile", "090-1234-5678", "JP", "+819012345678"},
+		{"japanese landline", "03 (1234) 5678", "jp", "+81312345678"},
+		{"full-width space", "090　1234　5678", "JP", "+819012345678"},
+		{"already E.164", "+81 90-1234-5678", "", "+819012345678"},
+		{"us number", "(415) 555-0123", "US", "+14155550123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.raw, tt.region)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("NormalizePhone(%q, %q) = %q, want %q", tt.raw, tt.region, got, tt.expected)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name   string
+		raw    string
+		region string
+	}{
+		{"empty", "", "JP"},
+		{"letters", "call me", "JP"},
+		{"too short", "090-1234", "JP"},
+		{"too long", "090-1234-5678-9", "JP"},
+		{"missing trunk prefix", "90-1234-5678", "JP"},
+		{"unsupported region", "090-1234-5678", "XX"},
+		{"international too long", "+1234567890123456", ""},
+		{"country code starting with 0", "+0123456789", ""},
+	}
+	for _, tt := range invalid {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			if got, err := NormalizePhone(tt.raw, tt.region); err == nil {
+				t.Errorf("Expected error, got: %q", got)
+			}
+		})
+	}
+}
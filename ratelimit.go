@@ -0,0 +1,107 @@
+package payjpv2
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate-limit state reported by the API in the X-RateLimit-* response headers.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends, zero if not reported
+	Reset time.Time
+}
+
+// RateLimitFromResponse parses the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+// (Unix seconds) headers of resp. It returns false if the limit or remaining count is missing or
+// invalid; an invalid reset time is left zero.
+//
+// Example usage:
+//
+//	resp, err := client.GetAllCustomersWithResponse(ctx, params)
+//	if err != nil {
+//	    return err
+//	}
+//	if rl, ok := payjpv2.RateLimitFromResponse(resp.HTTPResponse); ok && rl.Remaining == 0 {
+//	    time.Sleep(time.Until(rl.Reset))
+//	}
+func RateLimitFromResponse(resp *http.Response) (*RateLimit, bool) {
+	if resp == nil {
+		return nil, false
+	}
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil || limit < 0 {
+		return nil, false
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining < 0 {
+		return nil, false
+	}
+	rl := &RateLimit{Limit: limit, Remaining: remaining}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil && reset > 0 {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl, true
+}
+
+// RateLimitOf is like RateLimitFromResponse for a generated response, such as the one returned by
+// Extract, or an *http.Response.
+//
+// Example usage:
+//
+//	resp, err := payjpv2.Extract(client.GetCustomerWithResponse(ctx, customerID))
+//	if err != nil {
+//	    return err
+//	}
+//	if rl, ok := payjpv2.RateLimitOf(resp); ok {
+//	    metrics.Gauge("payjp.ratelimit.remaining", rl.Remaining)
+//	}
+func RateLimitOf(resp any) (*RateLimit, bool) {
+	return RateLimitFromResponse(httpResponseOf(resp))
+}
+
+// withRateLimitTracking returns a ClientOption that installs the SDK transport, which records
+// the rate limit of every response for RateLimitSnapshot
+func withRateLimitTracking() ClientOption {
+	return func(c *Client) error {
+		sdkDoerFor(c)
+		return nil
+	}
+}
+
+// recordRateLimit stores the rate limit reported by resp, if any, as the latest snapshot
+func (d *sdkDoer) recordRateLimit(resp *http.Response) {
+	if rl, ok := RateLimitFromResponse(resp); ok {
+		d.rateLimit.Store(rl)
+	}
+}
+
+// RateLimitSnapshot returns the rate limit reported by the most recent response that carried
+// rate-limit headers, so callers can throttle before running out of requests. It returns false
+// until such a response has been received, or for clients not created with
+// NewPayjpClientWithResponses.
+//
+// Example usage:
+//
+//	if rl, ok := client.RateLimitSnapshot(); ok && rl.Remaining < 10 {
+//	    time.Sleep(time.Until(rl.Reset))
+//	}
+func (c *ClientWithResponses) RateLimitSnapshot() (RateLimit, bool) {
+	client, ok := c.ClientInterface.(*Client)
+	if !ok {
+		return RateLimit{}, false
+	}
+	d, ok := client.Client.(*sdkDoer)
+	if !ok {
+		return RateLimit{}, false
+	}
+	rl := d.rateLimit.Load()
+	if rl == nil {
+		return RateLimit{}, false
+	}
+	return *rl, true
+}
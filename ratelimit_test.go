@@ -0,0 +1,129 @@
+package payjpv2
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitFromResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected *RateLimit
+	}{
+		{
+			name:     "all headers",
+			header:   http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"42"}, "X-Ratelimit-Reset": {"1704067200"}},
+			expected: &RateLimit{Limit: 100, Remaining: 42, Reset: time.Unix(1704067200, 0)},
+		},
+		{
+			name:     "without reset",
+			header:   http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"0"}},
+			expected: &RateLimit{Limit: 100, Remaining: 0},
+		},
+		{
+			name:     "garbage reset",
+			header:   http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"1"}, "X-Ratelimit-Reset": {"soon"}},
+			expected: &RateLimit{Limit: 100, Remaining: 1},
+		},
+		{name: "missing headers", header: http.Header{}},
+		{name: "missing remaining", header: http.Header{"X-Ratelimit-Limit": {"100"}}},
+		{name: "garbage limit", header: http.Header{"X-Ratelimit-Limit": {"many"}, "X-Ratelimit-Remaining": {"1"}}},
+		{name: "negative remaining", header: http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"-1"}}},
+		{name: "garbage remaining", header: http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"1.5"}}},
+		{name: "empty limit", header: http.Header{"X-Ratelimit-Limit": {""}, "X-Ratelimit-Remaining": {"1"}}},
+		{
+			name:     "non-positive reset",
+			header:   http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"1"}, "X-Ratelimit-Reset": {"0"}},
+			expected: &RateLimit{Limit: 100, Remaining: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl, ok := RateLimitFromResponse(&http.Response{Header: tt.header})
+			if tt.expected == nil {
+				if ok || rl != nil {
+					t.Errorf("Expected no rate limit, got: %+v", rl)
+				}
+				return
+			}
+			if !ok || rl.Limit != tt.expected.Limit || rl.Remaining != tt.expected.Remaining || !rl.Reset.Equal(tt.expected.Reset) {
+				t.Errorf("Rate limit incorrect. Got: %+v, Expected: %+v", rl, tt.expected)
+			}
+		})
+	}
+
+	t.Run("nil response", func(t *testing.T) {
+		if _, ok := RateLimitFromResponse(nil); ok {
+			t.Error("Expected no rate limit")
+		}
+	})
+}
+
+func TestRateLimitOf(t *testing.T) {
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			resp := jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`)
+			resp.Header.Set("X-RateLimit-Limit", "100")
+			resp.Header.Set("X-RateLimit-Remaining", "99")
+			return resp, nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("reads the headers of an extracted response", func(t *testing.T) {
+		resp, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rl, ok := RateLimitOf(resp)
+		if !ok || rl.Limit != 100 || rl.Remaining != 99 {
+			t.Errorf("Rate limit incorrect. Got: %+v, %v, Expected: limit 100, remaining 99", rl, ok)
+		}
+	})
+
+	t.Run("reports responses without rate-limit headers", func(t *testing.T) {
+		for _, resp := range []any{nil, "response", &GetCustomerResponse{}} {
+			if _, ok := RateLimitOf(resp); ok {
+				t.Errorf("Expected no rate limit for %#v", resp)
+			}
+		}
+	})
+}
+
+func TestRateLimitSnapshot(t *testing.T) {
+	remaining := []string{"9", "8"}
+	calls := 0
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			resp := jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`)
+			if calls < len(remaining) {
+				resp.Header.Set("X-RateLimit-Limit", "10")
+				resp.Header.Set("X-RateLimit-Remaining", remaining[calls])
+			}
+			calls++
+			return resp, nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, ok := client.RateLimitSnapshot(); ok {
+		t.Error("Expected no snapshot before any response")
+	}
+	for i, expected := range []int{9, 8, 8} {
+		if _, err := client.GetAllCustomersWithResponse(context.Background(), nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rl, ok := client.RateLimitSnapshot()
+		if !ok || rl.Limit != 10 || rl.Remaining != expected {
+			t.Errorf("Snapshot after request %d incorrect. Got: %+v, %v, Expected remaining: %d", i+1, rl, ok, expected)
+		}
+	}
+}
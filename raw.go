@@ -0,0 +1,98 @@
+package payjpv2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DoRawMap sends a request to an endpoint the generated client does not cover yet and decodes
+// the JSON response into a generic map. path is relative to the client's base URL, e.g.
+// "/v2/customers/cus_xxx", and may include a query string. body, if not nil, is sent as JSON.
+//
+// Numbers are decoded as json.Number to keep their precision. Error responses are returned as
+// an *APIError together with the response. The response body has been read, but can be read
+// again from the returned response.
+//
+// Example usage:
+//
+//	customer, _, err := client.DoRawMap(ctx, http.MethodGet, "/v2/customers/cus_xxx", nil)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(customer["email"])
+func (c *ClientWithResponses) DoRawMap(ctx context.Context, method, path string, body any, reqEditors ...RequestEditorFn) (map[string]any, *http.Response, error) {
+	client, err := c.client()
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := newRawRequest(ctx, client.Server, method, path, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := client.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := client.Client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, resp, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	if apiErr := apiErrorFromHTTPResponse(resp, data); apiErr != nil {
+		return nil, resp, apiErr
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, resp, nil
+	}
+	var result map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, resp, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result, resp, nil
+}
+
+// newRawRequest builds a request for path relative to server, encoding body as JSON if not nil
+func newRawRequest(ctx context.Context, server, method, path string, body any) (*http.Request, error) {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(path, "/") {
+		path = "." + path
+	}
+	queryURL, err := serverURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		bodyReader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, queryURL.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
@@ -0,0 +1,92 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestDoRawMap(t *testing.T) {
+	newClient := func(t *testing.T, handler func(req *http.Request) *http.Response) *ClientWithResponses {
+		t.Helper()
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return handler(req), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("decodes a raw GET into a map", func(t *testing.T) {
+		client := newClient(t, func(req *http.Request) *http.Response {
+			if req.Method != http.MethodGet {
+				t.Errorf("Method incorrect. Got: %s, Expected: GET", req.Method)
+			}
+			if req.URL.Path != "/v2/customers/cus_1" || req.URL.RawQuery != "expand=x" {
+				t.Errorf("URL incorrect. Got: %s", req.URL)
+			}
+			if req.Header.Get("Authorization") != "Bearer sk_test_example" {
+				t.Errorf("Authorization incorrect. Got: %s", req.Header.Get("Authorization"))
+			}
+			return jsonResponse(200, `{"id":"cus_1","object":"customer","balance":12345678901234567890,"metadata":{"plan":"pro"}}`)
+		})
+
+		result, resp, err := client.DoRawMap(context.Background(), http.MethodGet, "/v2/customers/cus_1?expand=x", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.StatusCode != 200 {
+			t.Errorf("Status incorrect. Got: %d, Expected: 200", resp.StatusCode)
+		}
+		if result["id"] != "cus_1" {
+			t.Errorf("ID incorrect. Got: %v, Expected: cus_1", result["id"])
+		}
+		if balance, ok := result["balance"].(json.Number); !ok || balance.String() != "12345678901234567890" {
+			t.Errorf("Balance incorrect. Got: %#v, Expected: json.Number 12345678901234567890", result["balance"])
+		}
+		if metadata, ok := result["metadata"].(map[string]any); !ok || metadata["plan"] != "pro" {
+			t.Errorf("Metadata incorrect. Got: %#v", result["metadata"])
+		}
+		if data, _ := io.ReadAll(resp.Body); len(data) == 0 {
+			t.Error("Expected the response body to be readable again")
+		}
+	})
+
+	t.Run("sends the body as JSON", func(t *testing.T) {
+		client := newClient(t, func(req *http.Request) *http.Response {
+			if req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("Content-Type incorrect. Got: %s, Expected: application/json", req.Header.Get("Content-Type"))
+			}
+			data, _ := io.ReadAll(req.Body)
+			if string(data) != `{"email":"a@example.com"}` {
+				t.Errorf("Body incorrect. Got: %s", data)
+			}
+			return jsonResponse(200, `{"id":"cus_1"}`)
+		})
+
+		if _, _, err := client.DoRawMap(context.Background(), http.MethodPost, "/v2/customers", map[string]string{"email": "a@example.com"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns API errors", func(t *testing.T) {
+		client := newClient(t, func(req *http.Request) *http.Response {
+			return jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`)
+		})
+
+		result, resp, err := client.DoRawMap(context.Background(), http.MethodGet, "/v2/unknown", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+			t.Errorf("Expected 404 APIError, got: %v", err)
+		}
+		if result != nil || resp == nil {
+			t.Errorf("Expected no result and the response, got: %v, %v", result, resp)
+		}
+	})
+}
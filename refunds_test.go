@@ -0,0 +1,177 @@
+package payjpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPaymentRefundResponseStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		succeeded bool
+		failed    bool
+		pending   bool
+	}{
+		{"succeeded", `{"id":"re_1","amount":1000,"payment_flow_id":"pfw_1","status":"succeeded"}`, true, false, false},
+		{"failed", `{"id":"re_2","amount":1000,"payment_flow_id":"pfw_1","status":"failed"}`, false, true, false},
+		{"pending", `{"id":"re_3","amount":1000,"payment_flow_id":"pfw_1","status":"pending"}`, false, false, true},
+		{"requires_action", `{"id":"re_4","amount":1000,"payment_flow_id":"pfw_1","status":"requires_action"}`, false, false, true},
+		{"canceled", `{"id":"re_5","amount":1000,"payment_flow_id":"pfw_1","status":"canceled"}`, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var refund PaymentRefundResponse
+			if err := json.Unmarshal([]byte(tt.body), &refund); err != nil {
+				t.Fatalf("Failed to decode refund: %v", err)
+			}
+			if refund.RefundSucceeded() != tt.succeeded {
+				t.Errorf("RefundSucceeded() = %v, want %v", refund.RefundSucceeded(), tt.succeeded)
+			}
+			if refund.RefundFailed() != tt.failed {
+				t.Errorf("RefundFailed() = %v, want %v", refund.RefundFailed(), tt.failed)
+			}
+			if refund.RefundPending() != tt.pending {
+				t.Errorf("RefundPending() = %v, want %v", refund.RefundPending(), tt.pending)
+			}
+		})
+	}
+
+	t.Run("nil refund", func(t *testing.T) {
+		var refund *PaymentRefundResponse
+		if refund.RefundSucceeded() || refund.RefundFailed() || refund.RefundPending() {
+			t.Error("Expected all predicates to be false for nil refund")
+		}
+	})
+}
+
+func TestPaymentFlowRefundTotals(t *testing.T) {
+	received := 10000
+	flow := &PaymentFlowResponse{Id: "pfw_1", Amount: 10000, AmountReceived: &received}
+	refunds := []PaymentRefundResponse{
+		{Id: "re_1", PaymentFlowId: "pfw_1", Amount: 3000, Status: PaymentRefundStatusSucceeded},
+		{Id: "re_2", PaymentFlowId: "pfw_1", Amount: 1000, Status: PaymentRefundStatusSucceeded},
+		{Id: "re_3", PaymentFlowId: "pfw_1", Amount: 2000, Status: PaymentRefundStatusPending},
+		{Id: "re_4", PaymentFlowId: "pfw_1", Amount: 500, Status: PaymentRefundStatusFailed},
+		{Id: "re_5", PaymentFlowId: "pfw_other", Amount: 9000, Status: PaymentRefundStatusSucceeded},
+	}
+
+	t.Run("partial refunds", func(t *testing.T) {
+		if got := flow.TotalRefunded(refunds); got != 4000 {
+			t.Errorf("TotalRefunded() = %d, want 4000", got)
+		}
+		if got := flow.RemainingRefundable(refunds); got != 4000 {
+			t.Errorf("RemainingRefundable() = %d, want 4000", got)
+		}
+	})
+
+	t.Run("no refunds", func(t *testing.T) {
+		if got := flow.TotalRefunded(nil); got != 0 {
+			t.Errorf("TotalRefunded() = %d, want 0", got)
+		}
+		if got := flow.RemainingRefundable(nil); got != 10000 {
+			t.Errorf("RemainingRefundable() = %d, want 10000", got)
+		}
+	})
+
+	t.Run("nothing received", func(t *testing.T) {
+		unpaid := &PaymentFlowResponse{Id: "pfw_2", Amount: 10000}
+		if got := unpaid.RemainingRefundable(nil); got != 0 {
+			t.Errorf("RemainingRefundable() = %d, want 0", got)
+		}
+	})
+}
+
+func TestAllRefunds(t *testing.T) {
+	refundJSON := func(id, createdAt string) string {
+		return fmt.Sprintf(`{"id":%q,"object":"payment_refund","amount":100,"livemode":false,"metadata":{},"payment_flow_id":"pfw_1","reason":"requested_by_customer","status":"succeeded","created_at":%q,"updated_at":%q}`,
+			id, createdAt, createdAt)
+	}
+	pages := map[string]string{
+		"": `{"object":"list","url":"/v2/payment_refunds","has_more":true,"data":[` +
+			refundJSON("re_4", "2024-03-01T00:00:00Z") + "," + refundJSON("re_3", "2024-02-15T00:00:00Z") + `]}`,
+		"re_3": `{"object":"list","url":"/v2/payment_refunds","has_more":false,"data":[` +
+			refundJSON("re_2", "2024-02-01T00:00:00Z") + "," + refundJSON("re_1", "2024-01-31T23:59:59Z") + `]}`,
+	}
+	var cursors []string
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			cursor := req.URL.Query().Get("starting_after")
+			cursors = append(cursors, cursor)
+			if req.URL.Query().Get("limit") != "100" {
+				t.Errorf("Limit incorrect. Got: %s, Expected: 100", req.URL.Query().Get("limit"))
+			}
+			return jsonResponse(200, pages[cursor]), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("pages and filters by date", func(t *testing.T) {
+		cursors = nil
+		from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		var ids []string
+		for refund, err := range client.AllRefunds(context.Background(), from, to) {
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ids = append(ids, refund.Id)
+		}
+
+		if strings.Join(ids, ",") != "re_3,re_2" {
+			t.Errorf("Refunds incorrect. Got: %v, Expected: [re_3 re_2]", ids)
+		}
+		if strings.Join(cursors, ",") != ",re_3" {
+			t.Errorf("Cursors incorrect. Got: %q", cursors)
+		}
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var ids []string
+		var gotErr error
+		for refund, err := range client.AllRefunds(ctx, time.Time{}, time.Time{}) {
+			if err != nil {
+				gotErr = err
+				break
+			}
+			ids = append(ids, refund.Id)
+			cancel()
+		}
+
+		if !errors.Is(gotErr, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got: %v", gotErr)
+		}
+		if strings.Join(ids, ",") != "re_4,re_3" {
+			t.Errorf("Refunds incorrect. Got: %v, Expected: [re_4 re_3]", ids)
+		}
+	})
+
+	t.Run("yields API errors", func(t *testing.T) {
+		failing, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(400, `{"status":400,"title":"Resource Missing","type":"about:blank"}`), nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		for _, err := range failing.AllRefunds(context.Background(), time.Time{}, time.Time{}) {
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || !apiErr.IsBadRequest() {
+				t.Errorf("Expected bad request APIError, got: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RequestBuilderFn builds an unsent request against the given server URL.
+// The generated NewXxxRequest functions bound to their arguments satisfy it.
+type RequestBuilderFn func(server string) (*http.Request, error)
+
+// errNotGeneratedClient is returned by helpers that need the underlying *Client
+var errNotGeneratedClient = errors.New("payjpv2: ClientWithResponses does not wrap a *Client")
+
+// client returns the generated *Client wrapped by c.
+func (c *ClientWithResponses) client() (*Client, error) {
+	client, ok := c.ClientInterface.(*Client)
+	if !ok {
+		return nil, errNotGeneratedClient
+	}
+	return client, nil
+}
+
+// BuildRequest returns the fully-resolved request for an operation without sending it.
+// The request is built against the client's base URL and passed through the client's
+// request editors and reqEditors, exactly as it would be before a real call.
+//
+// Example usage:
+//
+//	req, err := client.BuildRequest(ctx, func(server string) (*http.Request, error) {
+//	    return payjpv2.NewGetAllCustomersRequest(server, params)
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("%s %s", req.Method, req.URL)
+func (c *ClientWithResponses) BuildRequest(ctx context.Context, build RequestBuilderFn, reqEditors ...RequestEditorFn) (*http.Request, error) {
+	client, err := c.client()
+	if err != nil {
+		return nil, err
+	}
+	req, err := build(client.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := client.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// WithContentType returns a RequestEditorFn that overrides the Content-Type header of a request,
+// e.g. for an endpoint expecting a vendor JSON type. The body is still encoded by the generated
+// client, so use a content type compatible with it.
+func WithContentType(contentType string) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		if contentType == "" {
+			return errors.New("content type cannot be empty")
+		}
+		req.Header.Set("Content-Type", contentType)
+		return nil
+	}
+}
+
+// WithCallTimeout returns a RequestEditorFn that bounds a single call by timeout d,
+// and a cleanup function that releases the derived context. Call cleanup once the
+// response has been read, typically with defer. The caller's own deadline still
+// applies, so a shorter deadline on the call context is never extended.
+//
+// Example usage:
+//
+//	timeout, cancel := payjpv2.WithCallTimeout(2 * time.Second)
+//	defer cancel()
+//	resp, err := client.GetCustomerWithResponse(ctx, customerID, timeout)
+func WithCallTimeout(d time.Duration) (RequestEditorFn, func()) {
+	var mu sync.Mutex
+	var cancels []context.CancelFunc
+
+	editor := func(ctx context.Context, req *http.Request) error {
+		callCtx, cancel := context.WithTimeout(req.Context(), d)
+		mu.Lock()
+		cancels = append(cancels, cancel)
+		mu.Unlock()
+		*req = *req.WithContext(callCtx)
+		return nil
+	}
+	cleanup := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, cancel := range cancels {
+			cancel()
+		}
+		cancels = nil
+	}
+	return editor, cleanup
+}
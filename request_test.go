@@ -0,0 +1,147 @@
+package payjpv2
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBuildRequest(t *testing.T) {
+	client, err := NewPayjpClientWithResponses("sk_test_key")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	limit := 10
+	params := &GetAllCustomersParams{Limit: &limit}
+	req, err := client.BuildRequest(context.Background(), func(server string) (*http.Request, error) {
+		return NewGetAllCustomersRequest(server, params)
+	}, WithIdempotencyKey("build-key"))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+
+	if req.Method != http.MethodGet {
+		t.Errorf("Method incorrect. Got: %s, Expected: GET", req.Method)
+	}
+	if req.URL.Host != "api.pay.jp" {
+		t.Errorf("Host incorrect. Got: %s, Expected: api.pay.jp", req.URL.Host)
+	}
+	if req.URL.Path != "/v2/customers" {
+		t.Errorf("Path incorrect. Got: %s, Expected: /v2/customers", req.URL.Path)
+	}
+	if got := req.URL.Query().Get("limit"); got != "10" {
+		t.Errorf("limit query incorrect. Got: %s, Expected: 10", got)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer sk_test_key" {
+		t.Errorf("Authorization header incorrect. Got: %s", got)
+	}
+	if got := req.Header.Get("Idempotency-Key"); got != "build-key" {
+		t.Errorf("Idempotency-Key header incorrect. Got: %s, Expected: build-key", got)
+	}
+}
+
+func TestWithContentType(t *testing.T) {
+	var contentType string
+	var body []byte
+	client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			contentType = req.Header.Get("Content-Type")
+			body, _ = io.ReadAll(req.Body)
+			return jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	description := "VIP"
+	_, err = client.CreateCustomerWithResponse(context.Background(), CreateCustomerJSONRequestBody{Description: &description},
+		WithContentType("application/vnd.payjp+json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if contentType != "application/vnd.payjp+json" {
+		t.Errorf("Content-Type header incorrect. Got: %s, Expected: application/vnd.payjp+json", contentType)
+	}
+	if string(body) != `{"description":"VIP"}` {
+		t.Errorf("Body incorrect. Got: %s, Expected: {\"description\":\"VIP\"}", body)
+	}
+
+	t.Run("rejects empty content type", func(t *testing.T) {
+		_, err := client.CreateCustomerWithResponse(context.Background(), CreateCustomerJSONRequestBody{}, WithContentType(""))
+		if err == nil {
+			t.Error("Expected error for empty content type, got nil")
+		}
+	})
+}
+
+// contextRoundTripper captures the request context for testing
+type contextRoundTripper struct {
+	capturedCtx context.Context
+}
+
+func (m *contextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.capturedCtx = req.Context()
+	return &http.Response{
+		StatusCode: 401,
+		Body:       http.NoBody,
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	t.Run("request context deadline reflects per-call timeout", func(t *testing.T) {
+		mockTransport := &contextRoundTripper{}
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(&http.Client{Transport: mockTransport}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		timeout, cancel := WithCallTimeout(5 * time.Second)
+		defer cancel()
+		start := time.Now()
+		_, _ = client.GetCustomerWithResponse(context.Background(), "cus_123", timeout)
+		end := time.Now()
+
+		deadline, ok := mockTransport.capturedCtx.Deadline()
+		if !ok {
+			t.Fatal("Expected request context to have a deadline")
+		}
+		if deadline.Before(start.Add(5*time.Second)) || deadline.After(end.Add(5*time.Second)) {
+			t.Errorf("Deadline incorrect. Got %v after start, Expected 5s after the call", deadline.Sub(start))
+		}
+
+		cancel()
+		if mockTransport.capturedCtx.Err() != context.Canceled {
+			t.Errorf("Expected cleanup to cancel the call context, got: %v", mockTransport.capturedCtx.Err())
+		}
+	})
+
+	t.Run("does not extend a shorter caller deadline", func(t *testing.T) {
+		mockTransport := &contextRoundTripper{}
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(&http.Client{Transport: mockTransport}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		ctx, cancelCtx := context.WithTimeout(context.Background(), time.Second)
+		defer cancelCtx()
+		callerDeadline, _ := ctx.Deadline()
+
+		timeout, cancel := WithCallTimeout(time.Hour)
+		defer cancel()
+		_, _ = client.GetCustomerWithResponse(ctx, "cus_123", timeout)
+
+		deadline, ok := mockTransport.capturedCtx.Deadline()
+		if !ok {
+			t.Fatal("Expected request context to have a deadline")
+		}
+		if !deadline.Equal(callerDeadline) {
+			t.Errorf("Deadline incorrect. Got: %v, Expected caller deadline: %v", deadline, callerDeadline)
+		}
+	})
+}
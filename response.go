@@ -0,0 +1,244 @@
+package payjpv2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the WithMaxResponseBytes limit.
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum size")
+
+// responseBody returns the buffered body of a generated response
+func responseBody(resp any) ([]byte, error) {
+	v := reflect.ValueOf(resp)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, errors.New("response is nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported response type %T", resp)
+	}
+	body := v.FieldByName("Body")
+	if !body.IsValid() || body.Type() != reflect.TypeOf([]byte(nil)) {
+		return nil, fmt.Errorf("response type %T has no Body", resp)
+	}
+	return body.Bytes(), nil
+}
+
+// RawBodyFromResponse returns the raw body bytes of a generated response. The generated clients
+// buffer the whole body while parsing, so the raw bytes remain available alongside the typed
+// Result, e.g. for auditing or logging. It returns nil for values that are not generated responses.
+//
+// Example usage:
+//
+//	resp, err := payjpv2.Extract(client.GetCustomerWithResponse(ctx, customerID))
+//	if err != nil {
+//	    return err
+//	}
+//	customer := resp.Result
+//	audit.Write(payjpv2.RawBodyFromResponse(resp))
+func RawBodyFromResponse(resp any) []byte {
+	body, err := responseBody(resp)
+	if err != nil {
+		return nil
+	}
+	return body
+}
+
+// httpResponseOf returns the HTTPResponse of a generated response, or resp itself if it is an *http.Response
+func httpResponseOf(resp any) *http.Response {
+	if httpResp, ok := resp.(*http.Response); ok {
+		return httpResp
+	}
+	v := reflect.ValueOf(resp)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	field := v.FieldByName("HTTPResponse")
+	if !field.IsValid() || field.Type() != reflect.TypeOf((*http.Response)(nil)) {
+		return nil
+	}
+	return field.Interface().(*http.Response)
+}
+
+// ServerTime returns the time reported by the API in the Date header of a generated response
+// or *http.Response. Compare it with the local clock to detect clock skew, which breaks
+// timestamp checks such as webhook tolerance. It returns false if the header is missing or invalid.
+//
+// Example usage:
+//
+//	resp, err := client.GetCustomerWithResponse(ctx, customerID)
+//	if err != nil {
+//	    return err
+//	}
+//	if serverTime, ok := payjpv2.ServerTime(resp); ok {
+//	    skew := time.Since(serverTime)
+//	    fmt.Printf("local clock is %s ahead of the API\n", skew)
+//	}
+func ServerTime(resp any) (time.Time, bool) {
+	httpResp := httpResponseOf(resp)
+	if httpResp == nil {
+		return time.Time{}, false
+	}
+	date := httpResp.Header.Get("Date")
+	if date == "" {
+		return time.Time{}, false
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return serverTime, true
+}
+
+// RequestIDHeader is the response header carrying the id PAY.JP assigned to the request
+const RequestIDHeader = "X-Request-Id"
+
+// RequestID returns the id PAY.JP assigned to the request of a generated response or
+// *http.Response, from the RequestIDHeader header. It returns false if the response has none.
+// For failed requests, the id is also available as APIError.RequestID.
+func RequestID(resp any) (string, bool) {
+	httpResp := httpResponseOf(resp)
+	if httpResp == nil {
+		return "", false
+	}
+	id := httpResp.Header.Get(RequestIDHeader)
+	return id, id != ""
+}
+
+// ObjectType returns the object field of an API value, e.g. "customer" or "list", or "" if it
+// has none. v can be a generated response, whose raw body is read, a resource such as
+// *CustomerResponse, or raw JSON bytes. It helps generic handling and debugging.
+//
+// Example usage:
+//
+//	resp, err := client.GetCustomerWithResponse(ctx, customerID)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(payjpv2.ObjectType(resp)) // customer
+func ObjectType(v any) string {
+	switch data := v.(type) {
+	case []byte:
+		return objectTypeOf(data)
+	case json.RawMessage:
+		return objectTypeOf(data)
+	}
+	if body, err := responseBody(v); err == nil {
+		return objectTypeOf(body)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+	field := rv.FieldByName("Object")
+	switch {
+	case !field.IsValid():
+		return ""
+	case field.Kind() == reflect.String:
+		return field.String()
+	case field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.String:
+		return field.Elem().String()
+	}
+	return ""
+}
+
+// objectTypeOf reads the object field of a JSON object
+func objectTypeOf(data []byte) string {
+	var object struct {
+		Object string `json:"object"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return ""
+	}
+	return object.Object
+}
+
+// WithMaxResponseBytes returns a ClientOption that bounds the memory used to buffer a response body.
+// Reading more than n bytes fails the call with ErrResponseTooLarge.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New("maximum response size must be positive")
+		}
+		sdkDoerFor(c).maxResponseBytes = n
+		return nil
+	}
+}
+
+// ErrMissingRequestID is returned with WithRequireRequestID when a successful response has no
+// RequestIDHeader header.
+var ErrMissingRequestID = errors.New("response has no request id")
+
+// WithRequireRequestID returns a ClientOption that fails calls whose successful response has no
+// RequestIDHeader header with ErrMissingRequestID, to detect responses that do not come from the
+// PAY.JP API, such as a misconfigured mock or proxy. Error responses are returned as usual.
+// It is off by default.
+func WithRequireRequestID() ClientOption {
+	return func(c *Client) error {
+		sdkDoerFor(c).requireRequestID = true
+		return nil
+	}
+}
+
+// requireRequestID returns resp, or ErrMissingRequestID if it is a successful response without a
+// request id
+func requireRequestID(resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || resp.Header.Get(RequestIDHeader) != "" {
+		return resp, nil
+	}
+	if resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	return nil, ErrMissingRequestID
+}
+
+// limitedBody fails reads once more than remaining bytes have been read from the body
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+// limitResponseBody wraps the body of resp so that at most n bytes can be read
+func limitResponseBody(resp *http.Response, n int64) {
+	if resp.Body != nil {
+		resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: n}
+	}
+}
+
+// Read implements io.Reader.
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// Read one byte past the limit to detect oversized bodies
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	return n, err
+}
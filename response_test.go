@@ -0,0 +1,245 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRawBodyFromResponse(t *testing.T) {
+	body := `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`
+	newClient := func(t *testing.T, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(200, body), nil
+			}),
+		})}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("returns the raw bytes alongside the decoded result", func(t *testing.T) {
+		resp, err := Extract(newClient(t).GetCustomerWithResponse(context.Background(), "cus_1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Result == nil || resp.Result.Id != "cus_1" {
+			t.Errorf("Result incorrect. Got: %+v", resp.Result)
+		}
+		if got := string(RawBodyFromResponse(resp)); got != body {
+			t.Errorf("Raw body incorrect. Got: %s, Expected: %s", got, body)
+		}
+	})
+
+	t.Run("returns nil for other values", func(t *testing.T) {
+		if RawBodyFromResponse(nil) != nil || RawBodyFromResponse("body") != nil || RawBodyFromResponse((*GetCustomerResponse)(nil)) != nil {
+			t.Error("Expected nil raw body")
+		}
+	})
+
+	t.Run("allows bodies within WithMaxResponseBytes", func(t *testing.T) {
+		client := newClient(t, WithMaxResponseBytes(int64(len(body))))
+		resp, err := Extract(client.GetCustomerWithResponse(context.Background(), "cus_1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := string(RawBodyFromResponse(resp)); got != body {
+			t.Errorf("Raw body incorrect. Got: %s, Expected: %s", got, body)
+		}
+	})
+
+	t.Run("fails bodies over WithMaxResponseBytes", func(t *testing.T) {
+		client := newClient(t, WithMaxResponseBytes(int64(len(body)-1)))
+		_, err := client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("Expected ErrResponseTooLarge, got: %v", err)
+		}
+	})
+
+	t.Run("rejects a non-positive limit", func(t *testing.T) {
+		_, err := NewPayjpClientWithResponses("sk_test_example", WithMaxResponseBytes(0))
+		if err == nil || !strings.Contains(err.Error(), "must be positive") {
+			t.Errorf("Expected error for zero limit, got: %v", err)
+		}
+	})
+}
+
+func TestServerTime(t *testing.T) {
+	t.Run("reads the Date header of a generated response", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				resp := jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`)
+				resp.Header.Set("Date", "Mon, 01 Jan 2024 09:30:00 GMT")
+				return resp, nil
+			}),
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		resp, err := client.GetAllCustomersWithResponse(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		serverTime, ok := ServerTime(resp)
+		expected := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+		if !ok || !serverTime.Equal(expected) {
+			t.Errorf("Server time incorrect. Got: %s, %v, Expected: %s, true", serverTime, ok, expected)
+		}
+	})
+
+	t.Run("reads the Date header of an http.Response", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"Date": {"Mon, 01 Jan 2024 09:30:00 GMT"}}}
+		if _, ok := ServerTime(resp); !ok {
+			t.Error("Expected a server time")
+		}
+	})
+
+	t.Run("reports missing or invalid dates", func(t *testing.T) {
+		for _, resp := range []any{
+			nil,
+			"response",
+			(*GetCustomerResponse)(nil),
+			&GetCustomerResponse{},
+			&GetCustomerResponse{HTTPResponse: &http.Response{Header: http.Header{}}},
+			&GetCustomerResponse{HTTPResponse: &http.Response{Header: http.Header{"Date": {"yesterday"}}}},
+		} {
+			if _, ok := ServerTime(resp); ok {
+				t.Errorf("Expected no server time for %#v", resp)
+			}
+		}
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	t.Run("reads the request id of a successful response", func(t *testing.T) {
+		resp := &GetCustomerResponse{HTTPResponse: &http.Response{Header: http.Header{"X-Request-Id": {"req_abc"}}}}
+		if id, ok := RequestID(resp); !ok || id != "req_abc" {
+			t.Errorf("Request id incorrect. Got: %s, %v, Expected: req_abc, true", id, ok)
+		}
+	})
+
+	t.Run("reports a missing request id", func(t *testing.T) {
+		for _, resp := range []any{nil, &GetCustomerResponse{}, &http.Response{Header: http.Header{}}} {
+			if _, ok := RequestID(resp); ok {
+				t.Errorf("Expected no request id for %#v", resp)
+			}
+		}
+	})
+}
+
+func TestWithRequireRequestID(t *testing.T) {
+	newClient := func(t *testing.T, status int, requestID string, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				resp := jsonResponse(status, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`)
+				if status >= 400 {
+					resp = jsonResponse(status, `{"status":404,"title":"Not Found","type":"about:blank"}`)
+				}
+				if requestID != "" {
+					resp.Header.Set("X-Request-Id", requestID)
+				}
+				return resp, nil
+			}),
+		})}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("accepts a response with a request id", func(t *testing.T) {
+		client := newClient(t, 200, "req_abc", WithRequireRequestID())
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("fails a response without a request id", func(t *testing.T) {
+		client := newClient(t, 200, "", WithRequireRequestID())
+		_, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+		if !errors.Is(err, ErrMissingRequestID) {
+			t.Errorf("Expected ErrMissingRequestID, got: %v", err)
+		}
+	})
+
+	t.Run("returns error responses as usual", func(t *testing.T) {
+		client := newClient(t, 404, "", WithRequireRequestID())
+		_, err := Extract(client.GetCustomerWithResponse(context.Background(), "cus_1"))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+			t.Errorf("Expected not found APIError, got: %v", err)
+		}
+	})
+
+	t.Run("is off by default", func(t *testing.T) {
+		client := newClient(t, 200, "")
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestObjectType(t *testing.T) {
+	body := `{"id":"cus_1","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`
+	client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/v2/customers" {
+				return jsonResponse(200, `{"object":"list","data":[`+body+`],"has_more":false,"url":"/v2/customers"}`), nil
+			}
+			return jsonResponse(200, body), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("reads object from a customer response", func(t *testing.T) {
+		resp, err := Extract(client.GetCustomerWithResponse(context.Background(), "cus_1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := ObjectType(resp); got != "customer" {
+			t.Errorf("Object type incorrect. Got: %s, Expected: customer", got)
+		}
+		if got := ObjectType(resp.Result); got != "customer" {
+			t.Errorf("Object type of the result incorrect. Got: %s, Expected: customer", got)
+		}
+	})
+
+	t.Run("reads object from a list response and its items", func(t *testing.T) {
+		resp, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := ObjectType(resp); got != "list" {
+			t.Errorf("Object type incorrect. Got: %s, Expected: list", got)
+		}
+		if got := ObjectType(resp.Result.Data[0]); got != "customer" {
+			t.Errorf("Object type of the item incorrect. Got: %s, Expected: customer", got)
+		}
+	})
+
+	t.Run("reads object from raw JSON", func(t *testing.T) {
+		if got := ObjectType([]byte(`{"object":"payment_flow"}`)); got != "payment_flow" {
+			t.Errorf("Object type incorrect. Got: %s, Expected: payment_flow", got)
+		}
+	})
+
+	t.Run("returns empty for values without object", func(t *testing.T) {
+		for _, v := range []any{nil, "customer", (*CustomerResponse)(nil), CustomerResponse{}, []byte("not json")} {
+			if got := ObjectType(v); got != "" {
+				t.Errorf("Expected empty object type for %#v, got: %s", v, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,391 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// defaultRetryJitter is the jitter of WithRetry's waits when no RetryPolicy is applied
+const defaultRetryJitter = 0.2
+
+// defaultMaxRetryAfter is the longest Retry-After waited for when WithRetryBackoff sets no maximum delay
+const defaultMaxRetryAfter = time.Minute
+
+// RetryHook is called before each retry with the number of the attempt that failed (starting
+// at 1), its status code (0 for a transport error), its error and the delay before the next attempt.
+type RetryHook func(attempt int, status int, err error, nextDelay time.Duration)
+
+// retrier retries failed requests with exponential backoff
+type retrier struct {
+	maxRetries  int
+	baseDelay   time.Duration
+	backoffBase time.Duration
+	hasBackoff  bool
+	maxDelay    time.Duration
+	maxElapsed  time.Duration
+	hook        RetryHook
+	shouldRetry func(resp *http.Response, err error) bool
+	jitter      float64
+}
+
+// RetryPolicy customizes which failed attempts WithRetry retries and how the waits vary.
+type RetryPolicy struct {
+	// ShouldRetry reports whether an attempt that returned resp or failed with err is retried.
+	// Nil retries transport errors, 429 and 5xx. Requests that are not safe to repeat are never
+	// retried, whatever ShouldRetry returns.
+	ShouldRetry func(resp *http.Response, err error) bool
+	// Jitter shortens each backoff wait by a random fraction of up to Jitter, from 0 (no jitter)
+	// to 1, so that clients failing at the same time do not retry in lockstep. Without a
+	// RetryPolicy, WithRetry uses a jitter of 0.2.
+	Jitter float64
+}
+
+// WithRetry returns a ClientOption that retries requests failing with a transport error, 429 or
+// 5xx up to maxRetries times, waiting baseDelay, then twice as long before each further retry.
+// Each wait is shortened by a random fraction of up to 20% so that clients do not retry in lockstep.
+// Only requests that are safe to repeat are retried: GET, HEAD, DELETE, and POST requests carrying
+// an Idempotency-Key. When a failed response has a Retry-After header, the retry waits as long as
+// it asks instead, unless that is longer than the maximum delay of WithRetryBackoff, or a minute
+// without one: then the call fails without waiting. The wait is cut short when the request context
+// is done. After a refused or reset connection, idle connections are closed so that the retry
+// dials afresh.
+// Use WithRetryPolicy to choose the failures that are retried and the jitter.
+// When the retries are exhausted, the call fails with a *RetryError holding every attempt's error.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithRetry(3, 500*time.Millisecond),
+//	)
+func WithRetry(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) error {
+		if maxRetries < 0 {
+			return errors.New("max retries cannot be negative")
+		}
+		if baseDelay < 0 {
+			return errors.New("base delay cannot be negative")
+		}
+		r := retrierFor(sdkDoerFor(c))
+		r.maxRetries = maxRetries
+		r.baseDelay = baseDelay
+		return nil
+	}
+}
+
+// WithRetryBackoff returns a ClientOption that tunes the backoff of the retries made by WithRetry:
+// the first retry waits base, each further retry twice as long as the previous one, and no wait
+// is longer than maxDelay. base takes precedence over the base delay given to WithRetry, whatever
+// the order of the options.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithRetry(8, time.Second),
+//	    payjpv2.WithRetryBackoff(200*time.Millisecond, 5*time.Second),
+//	)
+func WithRetryBackoff(base, maxDelay time.Duration) ClientOption {
+	return func(c *Client) error {
+		if base < 0 {
+			return errors.New("base delay cannot be negative")
+		}
+		if maxDelay < base {
+			return errors.New("max delay cannot be less than the base delay")
+		}
+		r := retrierFor(sdkDoerFor(c))
+		r.backoffBase = base
+		r.hasBackoff = true
+		r.maxDelay = maxDelay
+		return nil
+	}
+}
+
+// WithRetryPolicy returns a ClientOption that applies p to the retries made by WithRetry.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithRetry(3, 500*time.Millisecond),
+//	    payjpv2.WithRetryPolicy(payjpv2.RetryPolicy{
+//	        ShouldRetry: func(resp *http.Response, err error) bool {
+//	            return err != nil || resp.StatusCode == http.StatusServiceUnavailable
+//	        },
+//	        Jitter: 0.5,
+//	    }),
+//	)
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		if p.Jitter < 0 || p.Jitter > 1 {
+			return errors.New("retry jitter must be between 0 and 1")
+		}
+		r := retrierFor(sdkDoerFor(c))
+		r.shouldRetry = p.ShouldRetry
+		r.jitter = p.Jitter
+		return nil
+	}
+}
+
+// WithRetryMaxElapsed returns a ClientOption that stops the retries made by WithRetry once the
+// time spent on a request, including the waits between attempts, would exceed d, failing the
+// call with a *RetryError. Retries stop at whichever of this budget and
+// WithRetry's maximum number of retries is reached first.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithRetry(10, 500*time.Millisecond),
+//	    payjpv2.WithRetryMaxElapsed(30*time.Second),
+//	)
+func WithRetryMaxElapsed(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("max elapsed time must be positive")
+		}
+		retrierFor(sdkDoerFor(c)).maxElapsed = d
+		return nil
+	}
+}
+
+// WithRetryHook returns a ClientOption that calls hook before each retry made by WithRetry.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithRetry(3, 500*time.Millisecond),
+//	    payjpv2.WithRetryHook(func(attempt, status int, err error, nextDelay time.Duration) {
+//	        log.Printf("attempt %d failed (status %d, err %v), retrying in %s", attempt, status, err, nextDelay)
+//	    }),
+//	)
+func WithRetryHook(hook RetryHook) ClientOption {
+	return func(c *Client) error {
+		if hook == nil {
+			return errors.New("retry hook cannot be nil")
+		}
+		retrierFor(sdkDoerFor(c)).hook = hook
+		return nil
+	}
+}
+
+// retrierFor returns the retrier of d, creating one if needed
+func retrierFor(d *sdkDoer) *retrier {
+	if d.retry == nil {
+		d.retry = &retrier{jitter: defaultRetryJitter}
+	}
+	return d.retry
+}
+
+// retryable reports whether req is safe to send more than once
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return req.Header.Get("Idempotency-Key") != ""
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether an attempt failed in a way worth retrying
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// RetryError is returned when WithRetry gives up on a request. It holds the error of every
+// attempt; a failed response is represented by an *APIError.
+type RetryError struct {
+	// Errors are the attempts' errors, in the order the attempts were made
+	Errors []error
+}
+
+// Error implements the error interface for RetryError.
+func (e *RetryError) Error() string {
+	if len(e.Errors) == 0 {
+		return "retries exhausted"
+	}
+	return fmt.Sprintf("retries exhausted after %d attempt(s): %v", len(e.Errors), e.Errors[len(e.Errors)-1])
+}
+
+// Unwrap returns the attempts' errors, most recent first, so errors.As and errors.Is
+// find the final attempt's error first.
+func (e *RetryError) Unwrap() []error {
+	errs := slices.Clone(e.Errors)
+	slices.Reverse(errs)
+	return errs
+}
+
+// attemptError returns the error of a failed attempt, reading and closing a failed response's body
+func attemptError(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if apiErr := apiErrorFromHTTPResponse(resp, body); apiErr != nil {
+		return apiErr
+	}
+	return fmt.Errorf("retried response with status %d", resp.StatusCode)
+}
+
+// ShouldRetryWithSameKey reports whether a failed request may be sent again with the same
+// idempotency key. That is the case when the outcome is unknown or the API failed transiently:
+// timeouts, connection failures, 429 and 5xx. It returns false for a nil error, cancellation,
+// and other API errors such as 422, which fail the same way when repeated, and 409, where the
+// key conflicts with another request.
+//
+// Example usage:
+//
+//	for attempt := 0; attempt < 3; attempt++ {
+//	    resp, err = payjpv2.Extract(client.CreateCustomerWithResponse(ctx, req, payjpv2.WithIdempotencyKey(key)))
+//	    if !payjpv2.ShouldRetryWithSameKey(err) {
+//	        break
+//	    }
+//	}
+func ShouldRetryWithSameKey(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	if isTimeout(err) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// retryAfter returns the wait asked for by the Retry-After header of resp, given in seconds or
+// as an HTTP date, if it has a valid one
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// isConnectionFailure reports whether err means the connection was refused or reset, e.g. because
+// the address a pooled connection or cached dial points to is gone
+func isConnectionFailure(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// delay returns the wait before the retry following the given failed attempt. Without a maximum
+// delay, the wait stops doubling before it would overflow.
+func (r *retrier) delay(attempt int) time.Duration {
+	d := r.baseDelay
+	if r.hasBackoff {
+		d = r.backoffBase
+	}
+	limit := r.maxDelay
+	if limit == 0 {
+		limit = math.MaxInt64
+	}
+	for i := 1; i < attempt && d < limit; i++ {
+		if d > limit/2 {
+			return limit
+		}
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+// nextDelay returns the wait before retrying the given failed attempt: the wait asked for by
+// its Retry-After header, or else the backoff delay with jitter applied. It returns false when
+// the Retry-After wait is longer than the retrier is willing to wait.
+func (r *retrier) nextDelay(attempt int, resp *http.Response, now time.Time) (time.Duration, bool) {
+	if d, ok := retryAfter(resp, now); ok {
+		limit := r.maxDelay
+		if limit == 0 {
+			limit = defaultMaxRetryAfter
+		}
+		return d, d <= limit
+	}
+	d := r.delay(attempt)
+	if r.jitter > 0 {
+		d -= time.Duration(rand.Float64() * r.jitter * float64(d))
+	}
+	return d, true
+}
+
+// retries reports whether a failed attempt is retried under the retrier's policy
+func (r *retrier) retries(resp *http.Response, err error) bool {
+	if r.shouldRetry != nil {
+		return r.shouldRetry(resp, err)
+	}
+	return shouldRetry(resp, err)
+}
+
+// do sends req with next, retrying failed attempts. closeIdle is called before retrying an
+// attempt whose connection was refused or reset, so that the retry dials a fresh connection.
+func (r *retrier) do(req *http.Request, clock Clock, next func(*http.Request) (*http.Response, error), closeIdle func()) (*http.Response, error) {
+	if r.maxRetries == 0 || !retryable(req) || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return next(req)
+	}
+
+	start := clock.Now()
+	var attemptErrs []error
+	for attempt := 1; ; attempt++ {
+		resp, err := next(req)
+		if !r.retries(resp, err) {
+			return resp, err
+		}
+
+		nextDelay, ok := r.nextDelay(attempt, resp, clock.Now())
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		attemptErrs = append(attemptErrs, attemptError(resp, err))
+		if !ok || attempt > r.maxRetries || r.maxElapsed > 0 && clock.Now().Sub(start)+nextDelay > r.maxElapsed {
+			return nil, &RetryError{Errors: attemptErrs}
+		}
+
+		if r.hook != nil {
+			r.hook(attempt, status, err, nextDelay)
+		}
+		if err := sleep(req.Context(), clock, nextDelay); err != nil {
+			return nil, err
+		}
+		if isConnectionFailure(err) {
+			closeIdle()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,647 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	newClient := func(t *testing.T, rt roundTripFunc, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{WithHTTPClient(&http.Client{Transport: rt})}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+	emptyList := `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`
+
+	t.Run("retries 5xx and transport errors until success", func(t *testing.T) {
+		attempts := 0
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			attempts++
+			switch attempts {
+			case 1:
+				return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+			case 2:
+				return nil, errors.New("connection reset by peer")
+			default:
+				return jsonResponse(200, emptyList), nil
+			}
+		}, WithRetry(3, time.Millisecond))
+
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 3", attempts)
+		}
+	})
+
+	t.Run("fails with a RetryError wrapping the last response when retries are exhausted", func(t *testing.T) {
+		attempts := 0
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return jsonResponse(429, `{"status":429,"title":"Too Many Requests","type":"about:blank"}`), nil
+		}, WithRetry(2, time.Millisecond))
+
+		resp, err := client.GetAllCustomersWithResponse(context.Background(), nil)
+		var retryErr *RetryError
+		var apiErr *APIError
+		if resp != nil || !errors.As(err, &retryErr) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("Expected a RetryError wrapping the 429 APIError and no response, got: %v, %v", resp, err)
+		}
+		if attempts != 3 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 3", attempts)
+		}
+	})
+
+	t.Run("does not retry 4xx", func(t *testing.T) {
+		attempts := 0
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return jsonResponse(400, `{"status":400,"title":"Bad Request","type":"about:blank"}`), nil
+		}, WithRetry(3, time.Millisecond))
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if attempts != 1 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 1", attempts)
+		}
+	})
+
+	t.Run("retries POST only with an idempotency key", func(t *testing.T) {
+		var bodies []string
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+		}, WithRetry(1, time.Millisecond))
+
+		_, _ = client.CreateCustomerWithResponse(context.Background(), CustomerCreateRequest{})
+		if len(bodies) != 1 {
+			t.Errorf("Attempt count without key incorrect. Got: %d, Expected: 1", len(bodies))
+		}
+
+		bodies = nil
+		_, _ = client.CreateCustomerWithResponse(context.Background(), CustomerCreateRequest{}, WithIdempotencyKey("key_1"))
+		if len(bodies) != 2 {
+			t.Fatalf("Attempt count with key incorrect. Got: %d, Expected: 2", len(bodies))
+		}
+		if bodies[0] == "" || bodies[0] != bodies[1] {
+			t.Errorf("Expected the body to be resent. Got: %q", bodies)
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+		}, WithRetry(1, time.Hour))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := client.GetAllCustomersWithResponse(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+		}
+	})
+
+	t.Run("closes idle connections before retrying a refused connection", func(t *testing.T) {
+		var events []string
+		transport := &idleClosingTransport{
+			roundTrip: func(req *http.Request) (*http.Response, error) {
+				events = append(events, "attempt")
+				if len(events) == 1 {
+					return nil, &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+				}
+				return jsonResponse(200, emptyList), nil
+			},
+			closeIdle: func() { events = append(events, "close idle") },
+		}
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{Transport: transport}), WithRetry(2, time.Millisecond))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := strings.Join(events, ", "); got != "attempt, close idle, attempt" {
+			t.Errorf("Events incorrect. Got: %s, Expected: attempt, close idle, attempt", got)
+		}
+	})
+
+	t.Run("keeps idle connections after other failures", func(t *testing.T) {
+		closed := false
+		attempts := 0
+		transport := &idleClosingTransport{
+			roundTrip: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				if attempts == 1 {
+					return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+				}
+				return jsonResponse(200, emptyList), nil
+			},
+			closeIdle: func() { closed = true },
+		}
+		client, err := NewPayjpClientWithResponses("sk_test_example", WithHTTPClient(&http.Client{Transport: transport}), WithRetry(2, time.Millisecond))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if closed {
+			t.Error("Expected idle connections to be kept after a 503")
+		}
+	})
+}
+
+// idleClosingTransport is a RoundTripper that records calls to CloseIdleConnections
+type idleClosingTransport struct {
+	roundTrip func(*http.Request) (*http.Response, error)
+	closeIdle func()
+}
+
+func (t *idleClosingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.roundTrip(req)
+}
+
+func (t *idleClosingTransport) CloseIdleConnections() {
+	t.closeIdle()
+}
+
+func TestWithRetryBackoff(t *testing.T) {
+	newClient := func(t *testing.T, clock *fakeClock, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+			})}),
+			WithClock(clock),
+		}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("doubles from base up to the ceiling", func(t *testing.T) {
+		clock := newFakeClock()
+		client := newClient(t, clock, WithRetry(6, time.Second), WithRetryBackoff(100*time.Millisecond, time.Second), WithRetryPolicy(RetryPolicy{Jitter: 0}))
+
+		if _, err := client.GetAllCustomersWithResponse(context.Background(), nil); err == nil {
+			t.Fatal("Expected error after retries are exhausted")
+		}
+		expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+		if fmt.Sprint(clock.sleeps) != fmt.Sprint(expected) {
+			t.Errorf("Delays incorrect. Got: %v, Expected: %v", clock.sleeps, expected)
+		}
+	})
+
+	t.Run("takes precedence over the WithRetry base delay in any order", func(t *testing.T) {
+		for _, opts := range [][]ClientOption{
+			{WithRetry(2, time.Hour), WithRetryBackoff(100*time.Millisecond, time.Second)},
+			{WithRetryBackoff(100*time.Millisecond, time.Second), WithRetry(2, time.Hour)},
+		} {
+			clock := newFakeClock()
+			client := newClient(t, clock, append(opts, WithRetryPolicy(RetryPolicy{Jitter: 0}))...)
+
+			_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+			expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+			if fmt.Sprint(clock.sleeps) != fmt.Sprint(expected) {
+				t.Errorf("Delays incorrect. Got: %v, Expected: %v", clock.sleeps, expected)
+			}
+		}
+	})
+
+	t.Run("does not overflow on many attempts", func(t *testing.T) {
+		r := &retrier{backoffBase: time.Second, hasBackoff: true, maxDelay: time.Minute}
+		if d := r.delay(100); d != time.Minute {
+			t.Errorf("Delay incorrect. Got: %s, Expected: 1m0s", d)
+		}
+	})
+
+	t.Run("does not overflow without a ceiling", func(t *testing.T) {
+		r := &retrier{baseDelay: time.Second}
+		for _, attempt := range []int{40, 64, 100} {
+			if d := r.delay(attempt); d != math.MaxInt64 {
+				t.Errorf("Delay of attempt %d incorrect. Got: %s, Expected: %s", attempt, d, time.Duration(math.MaxInt64))
+			}
+		}
+		if d := r.delay(3); d != 4*time.Second {
+			t.Errorf("Delay incorrect. Got: %s, Expected: 4s", d)
+		}
+	})
+
+	t.Run("rejects a ceiling below the base", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_example", WithRetryBackoff(time.Second, time.Millisecond)); err == nil {
+			t.Error("Expected error for max below base")
+		}
+	})
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	newClient := func(t *testing.T, attempts *int, status int, clock *fakeClock, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				*attempts++
+				return jsonResponse(status, fmt.Sprintf(`{"status":%d,"title":"%s","type":"about:blank"}`, status, http.StatusText(status))), nil
+			})}),
+			WithClock(clock),
+		}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+	retryConflicts := RetryPolicy{ShouldRetry: func(resp *http.Response, err error) bool {
+		return err == nil && resp.StatusCode == http.StatusConflict
+	}}
+
+	t.Run("retries the statuses chosen by ShouldRetry", func(t *testing.T) {
+		attempts := 0
+		client := newClient(t, &attempts, http.StatusConflict, newFakeClock(), WithRetry(2, time.Second), WithRetryPolicy(retryConflicts))
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if attempts != 3 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 3", attempts)
+		}
+	})
+
+	t.Run("does not retry statuses rejected by ShouldRetry", func(t *testing.T) {
+		attempts := 0
+		client := newClient(t, &attempts, http.StatusServiceUnavailable, newFakeClock(), WithRetry(2, time.Second), WithRetryPolicy(retryConflicts))
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if attempts != 1 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 1", attempts)
+		}
+	})
+
+	t.Run("never retries a POST without an idempotency key", func(t *testing.T) {
+		attempts := 0
+		client := newClient(t, &attempts, http.StatusConflict, newFakeClock(), WithRetry(2, time.Second), WithRetryPolicy(retryConflicts))
+
+		_, _ = client.CreateCustomerWithResponse(context.Background(), CustomerCreateRequest{})
+		if attempts != 1 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 1", attempts)
+		}
+	})
+
+	t.Run("shortens waits by at most the jitter", func(t *testing.T) {
+		attempts := 0
+		clock := newFakeClock()
+		client := newClient(t, &attempts, http.StatusServiceUnavailable, clock, WithRetry(5, time.Second), WithRetryPolicy(RetryPolicy{Jitter: 0.5}))
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if len(clock.sleeps) != 5 {
+			t.Fatalf("Sleep count incorrect. Got: %d, Expected: 5", len(clock.sleeps))
+		}
+		for i, d := range clock.sleeps {
+			backoff := time.Second << i
+			if d > backoff || d < backoff/2 {
+				t.Errorf("Sleep %d out of range. Got: %s, Expected: between %s and %s", i, d, backoff/2, backoff)
+			}
+		}
+	})
+
+	t.Run("shortens waits by up to 20% by default", func(t *testing.T) {
+		attempts := 0
+		clock := newFakeClock()
+		client := newClient(t, &attempts, http.StatusServiceUnavailable, clock, WithRetry(5, time.Second))
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if len(clock.sleeps) != 5 {
+			t.Fatalf("Sleep count incorrect. Got: %d, Expected: 5", len(clock.sleeps))
+		}
+		shortened := false
+		for i, d := range clock.sleeps {
+			backoff := time.Second << i
+			if d > backoff || d < backoff*8/10 {
+				t.Errorf("Sleep %d out of range. Got: %s, Expected: between %s and %s", i, d, backoff*8/10, backoff)
+			}
+			shortened = shortened || d < backoff
+		}
+		if !shortened {
+			t.Errorf("Expected jitter to shorten the waits. Got: %v", clock.sleeps)
+		}
+	})
+
+	t.Run("rejects a jitter above 1", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_example", WithRetryPolicy(RetryPolicy{Jitter: 1.5})); err == nil {
+			t.Error("Expected error for jitter above 1")
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Run("waits as long as Retry-After asks", func(t *testing.T) {
+		clock := newFakeClock()
+		attempts := 0
+		client, err := NewPayjpClientWithResponses("sk_test_example",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				switch attempts {
+				case 1:
+					resp := jsonResponse(429, `{"status":429,"title":"Too Many Requests","type":"about:blank"}`)
+					resp.Header.Set("Retry-After", "7")
+					return resp, nil
+				case 2:
+					resp := jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`)
+					resp.Header.Set("Retry-After", clock.Now().Add(30*time.Second).Format(http.TimeFormat))
+					return resp, nil
+				default:
+					return jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`), nil
+				}
+			})}),
+			WithClock(clock),
+			WithRetry(3, time.Second),
+			WithRetryPolicy(RetryPolicy{Jitter: 1}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []time.Duration{7 * time.Second, 30 * time.Second}
+		if fmt.Sprint(clock.sleeps) != fmt.Sprint(expected) {
+			t.Errorf("Sleeps incorrect. Got: %v, Expected: %v", clock.sleeps, expected)
+		}
+	})
+
+	t.Run("gives up when Retry-After is longer than the maximum delay", func(t *testing.T) {
+		for _, tt := range []struct {
+			name string
+			opts []ClientOption
+		}{
+			{"default maximum", nil},
+			{"WithRetryBackoff maximum", []ClientOption{WithRetryBackoff(time.Second, 10*time.Second)}},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				clock := newFakeClock()
+				attempts := 0
+				opts := append([]ClientOption{
+					WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+						attempts++
+						resp := jsonResponse(429, `{"status":429,"title":"Too Many Requests","type":"about:blank"}`)
+						resp.Header.Set("Retry-After", "86400")
+						return resp, nil
+					})}),
+					WithClock(clock),
+					WithRetry(3, time.Second),
+				}, tt.opts...)
+				client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+				if err != nil {
+					t.Fatalf("Failed to create client: %v", err)
+				}
+
+				_, err = Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || !apiErr.IsRateLimited() {
+					t.Errorf("Expected the 429 APIError, got: %v", err)
+				}
+				if attempts != 1 || len(clock.sleeps) != 0 {
+					t.Errorf("Expected no retry. Got: %d attempts, sleeps %v", attempts, clock.sleeps)
+				}
+			})
+		}
+	})
+
+	t.Run("ignores invalid values", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		for _, value := range []string{"", "-1", "soon"} {
+			resp := jsonResponse(429, `{}`)
+			resp.Header.Set("Retry-After", value)
+			if d, ok := retryAfter(resp, now); ok {
+				t.Errorf("retryAfter(%q) incorrect. Got: %s, Expected: none", value, d)
+			}
+		}
+	})
+}
+
+func TestWithRetryMaxElapsed(t *testing.T) {
+	newClient := func(t *testing.T, clock *fakeClock, attempts *int, requestTime time.Duration, opts ...ClientOption) *ClientWithResponses {
+		t.Helper()
+		opts = append([]ClientOption{
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				*attempts++
+				clock.advance(requestTime)
+				return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+			})}),
+			WithClock(clock),
+		}, opts...)
+		client, err := NewPayjpClientWithResponses("sk_test_example", opts...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	t.Run("stops retrying when the budget would be exceeded", func(t *testing.T) {
+		clock := newFakeClock()
+		attempts := 0
+		client := newClient(t, clock, &attempts, 0, WithRetry(10, time.Second), WithRetryMaxElapsed(5*time.Second), WithRetryPolicy(RetryPolicy{Jitter: 0}))
+
+		_, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected the last 503 APIError, got: %v", err)
+		}
+		// waits of 1s and 2s fit in the budget, the following 4s does not
+		if attempts != 3 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 3", attempts)
+		}
+		if expected := []time.Duration{time.Second, 2 * time.Second}; len(clock.sleeps) != 2 || clock.sleeps[0] != expected[0] || clock.sleeps[1] != expected[1] {
+			t.Errorf("Sleeps incorrect. Got: %v, Expected: %v", clock.sleeps, expected)
+		}
+	})
+
+	t.Run("counts the time spent on requests", func(t *testing.T) {
+		clock := newFakeClock()
+		attempts := 0
+		client := newClient(t, clock, &attempts, 3*time.Second, WithRetry(10, time.Second), WithRetryMaxElapsed(5*time.Second))
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		// 3s request + 1s wait fit in the budget, 7s + 2s does not
+		if attempts != 2 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 2", attempts)
+		}
+	})
+
+	t.Run("max retries is reached first", func(t *testing.T) {
+		clock := newFakeClock()
+		attempts := 0
+		client := newClient(t, clock, &attempts, 0, WithRetry(2, time.Second), WithRetryMaxElapsed(time.Hour))
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if attempts != 3 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 3", attempts)
+		}
+	})
+
+	t.Run("rejects a non-positive budget", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_example", WithRetryMaxElapsed(0)); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestRetryError(t *testing.T) {
+	t.Run("holds every attempt's error when retries are exhausted", func(t *testing.T) {
+		attempts := 0
+		client, err := NewPayjpClientWithResponses("sk_test_example",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				switch attempts {
+				case 1:
+					return nil, errors.New("connection reset by peer")
+				case 2:
+					return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+				default:
+					return jsonResponse(502, `{"status":502,"title":"Bad Gateway","type":"about:blank"}`), nil
+				}
+			})}),
+			WithRetry(2, time.Millisecond),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, err = Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("Expected RetryError, got: %v", err)
+		}
+		if len(retryErr.Errors) != 3 {
+			t.Fatalf("Error count incorrect. Got: %d, Expected: 3", len(retryErr.Errors))
+		}
+		if !strings.Contains(retryErr.Errors[0].Error(), "connection reset by peer") {
+			t.Errorf("First error incorrect. Got: %v", retryErr.Errors[0])
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || apiErr.Body == nil {
+			t.Errorf("Expected the final 502 APIError, got: %v", apiErr)
+		}
+		if !strings.Contains(err.Error(), "3 attempt(s)") {
+			t.Errorf("Error message incorrect. Got: %s", err.Error())
+		}
+	})
+
+	t.Run("is not returned when an attempt succeeds", func(t *testing.T) {
+		attempts := 0
+		client, err := NewPayjpClientWithResponses("sk_test_example",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				if attempts == 1 {
+					return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+				}
+				return jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`), nil
+			})}),
+			WithRetry(2, time.Millisecond),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, err = Extract(client.GetCustomerWithResponse(context.Background(), "cus_1"))
+		var retryErr *RetryError
+		var apiErr *APIError
+		if errors.As(err, &retryErr) || !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+			t.Errorf("Expected a plain not found APIError, got: %v", err)
+		}
+	})
+}
+
+func TestWithRetryHook(t *testing.T) {
+	t.Run("fires before each retry", func(t *testing.T) {
+		attempts := 0
+		type call struct {
+			attempt   int
+			status    int
+			err       error
+			nextDelay time.Duration
+		}
+		var calls []call
+		client, err := NewPayjpClientWithResponses("sk_test_example",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				if attempts < 3 {
+					return jsonResponse(502, `{"status":502,"title":"Bad Gateway","type":"about:blank"}`), nil
+				}
+				return jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`), nil
+			})}),
+			WithRetry(3, time.Millisecond),
+			WithRetryPolicy(RetryPolicy{Jitter: 0}),
+			WithRetryHook(func(attempt, status int, err error, nextDelay time.Duration) {
+				calls = append(calls, call{attempt, status, err, nextDelay})
+			}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []call{{1, 502, nil, time.Millisecond}, {2, 502, nil, 2 * time.Millisecond}}
+		if len(calls) != len(expected) {
+			t.Fatalf("Hook call count incorrect. Got: %d, Expected: %d", len(calls), len(expected))
+		}
+		for i := range expected {
+			if calls[i] != expected[i] {
+				t.Errorf("Hook call %d incorrect. Got: %+v, Expected: %+v", i, calls[i], expected[i])
+			}
+		}
+	})
+
+	t.Run("rejects a nil hook", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_example", WithRetryHook(nil)); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestShouldRetryWithSameKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"timeout", timeoutError{}, true},
+		{"deadline exceeded", fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{"canceled", context.Canceled, false},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"409 idempotency conflict", &APIError{StatusCode: 409}, false},
+		{"422 validation error", &APIError{StatusCode: 422}, false},
+		{"429 rate limited", &APIError{StatusCode: 429}, true},
+		{"503 service unavailable", &APIError{StatusCode: 503}, true},
+		{"wrapped 503", fmt.Errorf("create customer: %w", &APIError{StatusCode: 503}), true},
+		{"other error", errors.New("invalid request"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldRetryWithSameKey(tt.err); got != tt.expected {
+				t.Errorf("ShouldRetryWithSameKey(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package payjpv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// WithStreamingResponses returns a ClientOption that keeps the SDK transport from reading response
+// bodies ahead of the caller, so large list responses fetched with the raw ClientInterface methods
+// (e.g. client.GetAllCustomers) can be processed with StreamList without loading them into memory.
+// WithCache and WithErrorEnvelopeDetection, which need the whole body, are not applied;
+// WithCassette, meant for tests, still records whole bodies.
+//
+// The *WithResponse methods still buffer the whole body to decode it; RawBodyFromResponse and
+// DecodeInto only apply to those, not to streamed responses.
+func WithStreamingResponses() ClientOption {
+	return func(c *Client) error {
+		sdkDoerFor(c).streaming = true
+		return nil
+	}
+}
+
+// StreamList returns an iterator over the items of a raw list response, decoding them one at a
+// time as the body is read. Error responses are yielded as an *APIError. The body is closed
+// when iteration ends.
+//
+// Example usage:
+//
+//	for customer, err := range payjpv2.StreamList[payjpv2.CustomerResponse](client.GetAllCustomers(ctx, params)) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(customer.Id)
+//	}
+func StreamList[T any](resp *http.Response, err error) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode >= 400 {
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			yield(nil, apiErrorFromHTTPResponse(resp, body))
+			return
+		}
+
+		dec := json.NewDecoder(resp.Body)
+		if err := expectDelim(dec, '{'); err != nil {
+			yield(nil, err)
+			return
+		}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to decode list response: %w", err))
+				return
+			}
+			if key != "data" {
+				var skip json.RawMessage
+				if err := dec.Decode(&skip); err != nil {
+					yield(nil, fmt.Errorf("failed to decode list response: %w", err))
+					return
+				}
+				continue
+			}
+
+			if err := expectDelim(dec, '['); err != nil {
+				yield(nil, err)
+				return
+			}
+			for dec.More() {
+				var item T
+				if err := dec.Decode(&item); err != nil {
+					yield(nil, fmt.Errorf("failed to decode list item: %w", err))
+					return
+				}
+				if !yield(&item, nil) {
+					return
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// expectDelim reads the next token of dec and checks that it is delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode list response: %w", err)
+	}
+	if tok != delim {
+		return fmt.Errorf("failed to decode list response: expected %s, got %v", delim, tok)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
+func (c *countingReader) Close() error { return nil }
+
+func TestStreamList(t *testing.T) {
+	const items = 1000
+	var sb strings.Builder
+	sb.WriteString(`{"object":"list","url":"/v2/customers","data":[`)
+	for i := range items {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"id":"cus_%d","object":"customer","livemode":false,"created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z","metadata":{}}`, i)
+	}
+	sb.WriteString(`],"has_more":false}`)
+	page := sb.String()
+
+	t.Run("decodes items without buffering the whole body", func(t *testing.T) {
+		body := &countingReader{r: strings.NewReader(page)}
+		client, err := NewPayjpClientWithResponses("sk_test_example",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 200,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       body,
+				}, nil
+			})}),
+			WithCache(NewMemoryCache(), time.Minute),
+			WithErrorEnvelopeDetection(),
+			WithStreamingResponses(),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		count := 0
+		for customer, err := range StreamList[CustomerResponse](client.GetAllCustomers(context.Background(), nil)) {
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count == 0 {
+				if customer.Id != "cus_0" {
+					t.Errorf("First customer incorrect. Got: %s, Expected: cus_0", customer.Id)
+				}
+				if body.read >= len(page)/2 {
+					t.Errorf("Expected the body to be streamed, but %d of %d bytes were read for the first item", body.read, len(page))
+				}
+			}
+			count++
+		}
+		if count != items {
+			t.Errorf("Item count incorrect. Got: %d, Expected: %d", count, items)
+		}
+	})
+
+	t.Run("stops reading when iteration stops", func(t *testing.T) {
+		body := &countingReader{r: strings.NewReader(page)}
+		resp := &http.Response{StatusCode: 200, Body: body}
+		for range StreamList[CustomerResponse](resp, nil) {
+			break
+		}
+		if body.read >= len(page) {
+			t.Errorf("Expected the body not to be read to the end, read %d bytes", body.read)
+		}
+	})
+
+	t.Run("yields API errors", func(t *testing.T) {
+		resp := jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`)
+		for _, err := range StreamList[CustomerResponse](resp, nil) {
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+				t.Errorf("Expected not found APIError, got: %v", err)
+			}
+		}
+	})
+
+	t.Run("yields decoding errors", func(t *testing.T) {
+		resp := jsonResponse(200, `{"object":"list","data":[{"id":"cus_1"},`)
+		var lastErr error
+		for _, err := range StreamList[CustomerResponse](resp, nil) {
+			lastErr = err
+		}
+		if lastErr == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Tenant is the per-tenant configuration resolved from the request context by clients created with NewContextClient.
+type Tenant struct {
+	// APIKey is the tenant's secret API key. Required.
+	APIKey string
+	// BaseURL overrides the API base URL for the tenant. Optional.
+	BaseURL string
+}
+
+// tenantContextKey is the context key for ContextWithTenant
+type tenantContextKey struct{}
+
+// ErrNoTenant is returned by clients created with NewContextClient when the request context has no Tenant.
+var ErrNoTenant = errors.New("context has no tenant; use ContextWithTenant")
+
+// ContextWithTenant returns a copy of ctx carrying the tenant configuration used by NewContextClient clients.
+func ContextWithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by ContextWithTenant, if any.
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(Tenant)
+	return tenant, ok
+}
+
+// NewContextClient creates a PAY.JP V2 client that resolves the API key and base URL from the
+// request context at call time, so a single client can serve many tenants. Requests whose
+// context has no Tenant fail with ErrNoTenant before they are sent.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewContextClient()
+//	if err != nil {
+//	    return err
+//	}
+//	ctx = payjpv2.ContextWithTenant(ctx, payjpv2.Tenant{APIKey: tenant.SecretKey})
+//	resp, err := client.GetCustomerWithResponse(ctx, customerID)
+func NewContextClient(opts ...ClientOption) (*ClientWithResponses, error) {
+	return newPayjpClient(withTenantFromContext(), opts...)
+}
+
+// withTenantFromContext returns a ClientOption that authenticates and routes each request
+// with the Tenant stored in its context.
+func withTenantFromContext() ClientOption {
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		tenant, ok := TenantFromContext(ctx)
+		if !ok {
+			return ErrNoTenant
+		}
+		if err := validateAPIKey(tenant.APIKey); err != nil {
+			return fmt.Errorf("invalid tenant: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tenant.APIKey))
+
+		if tenant.BaseURL == "" {
+			return nil
+		}
+		base, err := url.Parse(strings.TrimSuffix(tenant.BaseURL, "/"))
+		if err != nil {
+			return fmt.Errorf("invalid tenant base URL: %w", err)
+		}
+		if base.Scheme == "" || base.Host == "" {
+			return fmt.Errorf("invalid tenant base URL: %s", tenant.BaseURL)
+		}
+		req.URL.Scheme = base.Scheme
+		req.URL.Host = base.Host
+		if req.URL.RawPath != "" {
+			req.URL.RawPath = base.EscapedPath() + req.URL.RawPath
+		}
+		req.URL.Path = base.Path + req.URL.Path
+		req.Host = base.Host
+		return nil
+	})
+}
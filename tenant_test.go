@@ -0,0 +1,76 @@
+package payjpv2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestNewContextClient(t *testing.T) {
+	var requests []*http.Request
+	client, err := NewContextClient(WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			return jsonResponse(404, `{"status":404,"title":"Not Found","type":"about:blank"}`), nil
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("resolves the tenant from the context per request", func(t *testing.T) {
+		requests = nil
+		ctxA := ContextWithTenant(context.Background(), Tenant{APIKey: "sk_test_tenant_a"})
+		ctxB := ContextWithTenant(context.Background(), Tenant{APIKey: "sk_test_tenant_b", BaseURL: "https://tenant-b.example.com/payjp/"})
+
+		_, _ = client.GetCustomerWithResponse(ctxA, "cus_1")
+		_, _ = client.GetCustomerWithResponse(ctxB, "cus_2")
+
+		if len(requests) != 2 {
+			t.Fatalf("Request count incorrect. Got: %d, Expected: 2", len(requests))
+		}
+		if got := requests[0].Header.Get("Authorization"); got != "Bearer sk_test_tenant_a" {
+			t.Errorf("Authorization header incorrect. Got: %s, Expected: Bearer sk_test_tenant_a", got)
+		}
+		if got := requests[0].URL.String(); got != "https://api.pay.jp/v2/customers/cus_1" {
+			t.Errorf("URL incorrect. Got: %s, Expected: https://api.pay.jp/v2/customers/cus_1", got)
+		}
+		if got := requests[1].Header.Get("Authorization"); got != "Bearer sk_test_tenant_b" {
+			t.Errorf("Authorization header incorrect. Got: %s, Expected: Bearer sk_test_tenant_b", got)
+		}
+		if got := requests[1].URL.String(); got != "https://tenant-b.example.com/payjp/v2/customers/cus_2" {
+			t.Errorf("URL incorrect. Got: %s, Expected: https://tenant-b.example.com/payjp/v2/customers/cus_2", got)
+		}
+		if got := requests[1].Header.Get("User-Agent"); got == "" {
+			t.Error("Expected default User-Agent header")
+		}
+	})
+
+	t.Run("errors without a tenant", func(t *testing.T) {
+		requests = nil
+		_, err := client.GetCustomerWithResponse(context.Background(), "cus_1")
+		if !errors.Is(err, ErrNoTenant) {
+			t.Errorf("Expected ErrNoTenant, got: %v", err)
+		}
+		if len(requests) != 0 {
+			t.Errorf("Expected no request to be sent, got: %d", len(requests))
+		}
+	})
+
+	t.Run("errors with an invalid tenant", func(t *testing.T) {
+		requests = nil
+		ctx := ContextWithTenant(context.Background(), Tenant{APIKey: "pk_test_public"})
+		if _, err := client.GetCustomerWithResponse(ctx, "cus_1"); err == nil {
+			t.Error("Expected error for invalid API key, got nil")
+		}
+
+		ctx = ContextWithTenant(context.Background(), Tenant{APIKey: "sk_test_key", BaseURL: "not a url"})
+		if _, err := client.GetCustomerWithResponse(ctx, "cus_1"); err == nil {
+			t.Error("Expected error for invalid base URL, got nil")
+		}
+		if len(requests) != 0 {
+			t.Errorf("Expected no request to be sent, got: %d", len(requests))
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// Package testcards provides PAY.JP test-mode card numbers and request builders,
+// so integration tests can exercise successful payments as well as failure paths.
+// The numbers only work with test API keys (sk_test_...).
+package testcards
+
+import (
+	"strings"
+	"time"
+
+	payjpv2 "github.com/payjp/payjpv2-go"
+)
+
+// Card numbers that succeed in test mode, by brand
+const (
+	Visa            = "4242424242424242"
+	Mastercard      = "5555555555554444"
+	JCB             = "3530111333300000"
+	AmericanExpress = "378282246310005"
+	DinersClub      = "30569309025904"
+	Discover        = "6011111111111117"
+)
+
+// Card numbers that trigger failures or extra steps in test mode
+const (
+	// CardDeclined is declined with card_declined when a payment is made
+	CardDeclined = "4000000000000002"
+	// CardExpired is declined with expired_card
+	CardExpired = "4000000000000069"
+	// CardIncorrectCVC is declined with incorrect_cvc
+	CardIncorrectCVC = "4000000000000127"
+	// CardProcessingError fails with processing_error
+	CardProcessingError = "4000000000000119"
+	// CardRequires3DS requires 3-D Secure authentication before the payment succeeds
+	CardRequires3DS = "4000000000003220"
+)
+
+// DefaultEmail is the billing email used by CreateRequest; card registration requires an email or phone number
+const DefaultEmail = "test@example.com"
+
+// Details returns card details for number with an expiry a few years ahead and a CVC
+// of the right length for the brand.
+//
+// Example usage:
+//
+//	card := testcards.Details(testcards.CardDeclined)
+func Details(number string) payjpv2.PaymentMethodCreateCardDetailsRequest {
+	cvc := "123"
+	if strings.HasPrefix(number, "34") || strings.HasPrefix(number, "37") {
+		cvc = "1234"
+	}
+	return payjpv2.PaymentMethodCreateCardDetailsRequest{
+		Number:   number,
+		ExpMonth: 12,
+		ExpYear:  time.Now().Year() + 5,
+		Cvc:      cvc,
+	}
+}
+
+// CardCreateRequest returns a card payment method create request for number.
+func CardCreateRequest(number string) payjpv2.PaymentMethodCardCreateRequest {
+	email := DefaultEmail
+	return payjpv2.PaymentMethodCardCreateRequest{
+		Type:           "card",
+		Card:           Details(number),
+		BillingDetails: payjpv2.PaymentMethodCardBillingDetailsRequest{Email: &email},
+	}
+}
+
+// CreateRequest returns a PaymentMethodCreateRequest registering a card with number,
+// ready to pass to CreatePaymentMethodWithResponse.
+//
+// Example usage:
+//
+//	body, err := testcards.CreateRequest(testcards.CardRequires3DS)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	resp, err := client.CreatePaymentMethodWithResponse(ctx, body)
+func CreateRequest(number string) (payjpv2.PaymentMethodCreateRequest, error) {
+	var req payjpv2.PaymentMethodCreateRequest
+	if err := req.FromPaymentMethodCardCreateRequest(CardCreateRequest(number)); err != nil {
+		return req, err
+	}
+	return req, nil
+}
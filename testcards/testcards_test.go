@@ -0,0 +1,48 @@
+package testcards
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetails(t *testing.T) {
+	t.Run("uses the given card number", func(t *testing.T) {
+		card := Details(CardDeclined)
+		if card.Number != "4000000000000002" {
+			t.Errorf("Number incorrect. Got: %s, Expected: 4000000000000002", card.Number)
+		}
+		if card.Cvc != "123" {
+			t.Errorf("CVC incorrect. Got: %s, Expected: 123", card.Cvc)
+		}
+		if card.ExpYear <= time.Now().Year() {
+			t.Errorf("Expiry year should be in the future. Got: %d", card.ExpYear)
+		}
+	})
+
+	t.Run("uses a four digit CVC for American Express", func(t *testing.T) {
+		if card := Details(AmericanExpress); card.Cvc != "1234" {
+			t.Errorf("CVC incorrect. Got: %s, Expected: 1234", card.Cvc)
+		}
+	})
+}
+
+func TestCreateRequest(t *testing.T) {
+	req, err := CreateRequest(CardRequires3DS)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	card, err := req.AsPaymentMethodCardCreateRequest()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if card.Type != "card" {
+		t.Errorf("Type incorrect. Got: %s, Expected: card", card.Type)
+	}
+	if card.Card.Number != CardRequires3DS {
+		t.Errorf("Number incorrect. Got: %s, Expected: %s", card.Card.Number, CardRequires3DS)
+	}
+	if card.BillingDetails.Email == nil || *card.BillingDetails.Email != DefaultEmail {
+		t.Errorf("Billing email incorrect. Got: %v", card.BillingDetails.Email)
+	}
+}
@@ -0,0 +1,287 @@
+package payjpv2
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// sdkDoer is the HttpRequestDoer installed by the SDK's transport options.
+// It holds the per-client SDK state and sends requests through base, which is
+// either the HttpRequestDoer supplied with WithHTTPClient or an SDK-owned *http.Client.
+type sdkDoer struct {
+	base     HttpRequestDoer
+	ownsBase bool
+
+	cache               *responseCache
+	retry               *retrier
+	cassette            *cassette
+	detectErrorEnvelope bool
+	maxResponseBytes    int64
+	maxPages            int
+
+	editors     *namedEditors
+	clock       Clock
+	streaming   bool
+	rewriteURLs []func(*url.URL)
+	rateLimit   atomic.Pointer[RateLimit]
+
+	logger        Logger
+	logRawHeaders bool
+
+	requestStartHeader bool
+	keyType            KeyType
+	timeout            time.Duration
+	requireRequestID   bool
+}
+
+// sdkDoerFor returns the sdkDoer of c, installing one around the current doer if needed.
+func sdkDoerFor(c *Client) *sdkDoer {
+	if d, ok := c.Client.(*sdkDoer); ok {
+		return d
+	}
+	d := &sdkDoer{base: c.Client}
+	if d.base == nil {
+		d.base, d.ownsBase = ownedBase(), true
+	}
+	c.Client = d
+	return d
+}
+
+// ownedBase returns a new SDK-owned base client, with a transport requiring TLS 1.2
+func ownedBase() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: transport}
+}
+
+// setHTTPClient sets the doer of c, as called by WithHTTPClient. Once a transport option has
+// installed an sdkDoer, doer becomes its base doer, so the options given before WithHTTPClient
+// keep applying. A nil doer restores an SDK-owned base client.
+func setHTTPClient(c *Client, doer HttpRequestDoer) {
+	d, ok := c.Client.(*sdkDoer)
+	if _, isSDKDoer := doer.(*sdkDoer); !ok || isSDKDoer {
+		c.Client = doer
+		return
+	}
+	if doer == nil {
+		d.base, d.ownsBase = ownedBase(), true
+		return
+	}
+	d.base, d.ownsBase = doer, false
+}
+
+// ownedTransport returns the transport of the SDK-owned base client, or nil if the base doer
+// was supplied by the user
+func (d *sdkDoer) ownedTransport() *http.Transport {
+	if !d.ownsBase {
+		return nil
+	}
+	client, ok := d.base.(*http.Client)
+	if !ok {
+		return nil
+	}
+	transport, _ := client.Transport.(*http.Transport)
+	return transport
+}
+
+// WithMinTLSVersion returns a ClientOption that sets the minimum TLS version negotiated by the
+// SDK-owned transport, e.g. tls.VersionTLS13. The SDK-owned transport defaults to TLS 1.2.
+//
+// It has no effect on a doer supplied with WithHTTPClient, before or after it; configure the
+// TLS settings of that client directly.
+func WithMinTLSVersion(v uint16) ClientOption {
+	return func(c *Client) error {
+		if v < tls.VersionTLS10 || v > tls.VersionTLS13 {
+			return errors.New("unsupported TLS version")
+		}
+		if transport := sdkDoerFor(c).ownedTransport(); transport != nil {
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+			transport.TLSClientConfig.MinVersion = v
+		}
+		return nil
+	}
+}
+
+// WithDialContext returns a ClientOption that makes the SDK-owned transport open connections with
+// dial, e.g. to resolve api.pay.jp to an internal egress proxy.
+//
+// It has no effect on a doer supplied with WithHTTPClient, before or after it; configure the
+// transport of that client directly.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ClientOption {
+	return func(c *Client) error {
+		if dial == nil {
+			return errors.New("dial function cannot be nil")
+		}
+		if transport := sdkDoerFor(c).ownedTransport(); transport != nil {
+			transport.DialContext = dial
+		}
+		return nil
+	}
+}
+
+// WithURLRewriter returns a ClientOption that calls fn with the URL of each request before it is
+// sent, e.g. to route requests through an API gateway that expects a path prefix. fn sees the
+// final URL, after the base URL and the request editors have been applied, and may change any
+// part of it, including the host. Rewriters run in the order the options are given.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithURLRewriter(func(u *url.URL) {
+//	        u.Host = "gateway.internal"
+//	        u.Path = "/payjp" + u.Path
+//	    }),
+//	)
+func WithURLRewriter(fn func(*url.URL)) ClientOption {
+	return func(c *Client) error {
+		if fn == nil {
+			return errors.New("URL rewriter cannot be nil")
+		}
+		d := sdkDoerFor(c)
+		d.rewriteURLs = append(d.rewriteURLs, fn)
+		return nil
+	}
+}
+
+// RequestStartHeader is the header carrying the send time set by WithRequestStartHeader
+const RequestStartHeader = "X-Request-Start"
+
+// WithRequestStartHeader returns a ClientOption that stamps each request, including each retry,
+// with the time it is sent in the RequestStartHeader header, as "t=" followed by Unix
+// microseconds, so that PAY.JP or a proxy can attribute network latency. The time is read from
+// the client's clock.
+func WithRequestStartHeader() ClientOption {
+	return func(c *Client) error {
+		sdkDoerFor(c).requestStartHeader = true
+		return nil
+	}
+}
+
+// WithTimeout returns a ClientOption that limits each call to d, including its retries and
+// the time spent reading the response body, like http.Client.Timeout. The limit is set on the
+// request context, so it also applies to a doer supplied with WithHTTPClient, before or after
+// it and with either constructor, without changing that client. A timed-out call returns an error wrapping
+// context.DeadlineExceeded.
+//
+// Example usage:
+//
+//	client, err := payjpv2.NewPayjpClientWithResponses(apiKey,
+//	    payjpv2.WithTimeout(30*time.Second),
+//	    payjpv2.WithRetry(3, 500*time.Millisecond),
+//	)
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("timeout must be positive")
+		}
+		sdkDoerFor(c).timeout = d
+		return nil
+	}
+}
+
+// cancelOnClose cancels the context of a request when its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// rewriteURL applies the URL rewriters of d to req
+func (d *sdkDoer) rewriteURL(req *http.Request) {
+	if len(d.rewriteURLs) == 0 {
+		return
+	}
+	u := *req.URL
+	for _, fn := range d.rewriteURLs {
+		fn(&u)
+	}
+	req.URL = &u
+	req.Host = u.Host
+}
+
+// Do implements HttpRequestDoer.
+// The URL is rewritten and the timeout applied first, then requests go through the response cache, then the retrier, then the cassette, then the base doer.
+func (d *sdkDoer) Do(req *http.Request) (*http.Response, error) {
+	d.rewriteURL(req)
+	if d.timeout <= 0 {
+		return d.do(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), d.timeout)
+	resp, err := d.do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// do sends req through the response cache, if any, and the retrier, then checks the request id
+func (d *sdkDoer) do(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+	if d.cache != nil && !d.streaming {
+		resp, err = d.cache.do(req, d.clockOrDefault(), d.sendWithRetry)
+	} else {
+		resp, err = d.sendWithRetry(req)
+	}
+	if err != nil || !d.requireRequestID {
+		return resp, err
+	}
+	return requireRequestID(resp)
+}
+
+// sendWithRetry sends req through the retrier, if any
+func (d *sdkDoer) sendWithRetry(req *http.Request) (*http.Response, error) {
+	if d.retry != nil {
+		return d.retry.do(req, d.clockOrDefault(), d.send, d.closeIdleConnections)
+	}
+	return d.send(req)
+}
+
+// closeIdleConnections closes the idle connections of the base doer, if it supports it
+func (d *sdkDoer) closeIdleConnections() {
+	if closer, ok := d.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// send sends req through the logger, if any, the cassette, if any, and the base doer
+func (d *sdkDoer) send(req *http.Request) (*http.Response, error) {
+	if d.requestStartHeader {
+		req.Header.Set(RequestStartHeader, "t="+strconv.FormatInt(d.clockOrDefault().Now().UnixMicro(), 10))
+	}
+	resp, err := d.logged(req, func(req *http.Request) (*http.Response, error) {
+		if d.cassette != nil {
+			return d.cassette.do(req, d.base)
+		}
+		return d.base.Do(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.recordRateLimit(resp)
+	if d.maxResponseBytes > 0 {
+		limitResponseBody(resp, d.maxResponseBytes)
+	}
+	if d.detectErrorEnvelope && !d.streaming {
+		return unwrapErrorEnvelope(resp)
+	}
+	return resp, nil
+}
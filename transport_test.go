@@ -0,0 +1,387 @@
+package payjpv2
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper for tests
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithHTTPClientOrder(t *testing.T) {
+	t.Run("WithHTTPClient after a transport option replaces the base doer", func(t *testing.T) {
+		httpClient := &http.Client{Transport: &mockRoundTripper{}}
+		client, err := NewPayjpClientWithResponses(
+			"sk_test_key",
+			WithCassette(t.TempDir()+"/cassette.json", RecordModeRecord),
+			WithHTTPClient(httpClient),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		doer, ok := client.ClientInterface.(*Client).Client.(*sdkDoer)
+		if !ok {
+			t.Fatal("Expected the SDK doer to be kept")
+		}
+		if doer.base != httpClient {
+			t.Error("Expected WithHTTPClient to set the base doer")
+		}
+		if doer.ownsBase {
+			t.Error("Expected a user-supplied base doer not to be SDK-owned")
+		}
+		if doer.cassette == nil {
+			t.Error("Expected the cassette to be kept")
+		}
+	})
+
+	t.Run("NewClientWithResponses keeps options given before WithHTTPClient", func(t *testing.T) {
+		attempts := 0
+		client, err := NewClientWithResponses(DEFAULT_BASE_URL,
+			WithRetry(2, time.Millisecond),
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+			})}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		_, _ = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if attempts != 3 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 3", attempts)
+		}
+	})
+
+	t.Run("nil WithHTTPClient restores an SDK-owned base client", func(t *testing.T) {
+		client, err := NewClientWithResponses(DEFAULT_BASE_URL, WithRetry(1, time.Millisecond), WithHTTPClient(nil))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		doer, ok := client.ClientInterface.(*Client).Client.(*sdkDoer)
+		if !ok || doer.retry == nil {
+			t.Fatal("Expected the SDK doer to be kept")
+		}
+		if !doer.ownsBase || doer.base == nil {
+			t.Error("Expected an SDK-owned base doer")
+		}
+	})
+
+	t.Run("transport option without WithHTTPClient owns its base client", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithCassette(t.TempDir()+"/cassette.json", RecordModeRecord))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		doer := client.ClientInterface.(*Client).Client.(*sdkDoer)
+		if !doer.ownsBase {
+			t.Error("Expected the base doer to be SDK-owned")
+		}
+	})
+}
+
+func TestWithMinTLSVersion(t *testing.T) {
+	minVersion := func(t *testing.T, client *ClientWithResponses) uint16 {
+		t.Helper()
+		transport := client.ClientInterface.(*Client).Client.(*sdkDoer).ownedTransport()
+		if transport == nil || transport.TLSClientConfig == nil {
+			t.Fatal("Expected an SDK-owned transport with a TLS config")
+		}
+		return transport.TLSClientConfig.MinVersion
+	}
+
+	t.Run("sets the SDK-owned transport's minimum version", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithMinTLSVersion(tls.VersionTLS13))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if got := minVersion(t, client); got != tls.VersionTLS13 {
+			t.Errorf("MinVersion incorrect. Got: %x, Expected: %x", got, tls.VersionTLS13)
+		}
+	})
+
+	t.Run("defaults the SDK-owned transport to TLS 1.2", func(t *testing.T) {
+		client, err := NewPayjpClientWithResponses("sk_test_key", WithMaxResponseBytes(1024))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if got := minVersion(t, client); got != tls.VersionTLS12 {
+			t.Errorf("MinVersion incorrect. Got: %x, Expected: %x", got, tls.VersionTLS12)
+		}
+	})
+
+	t.Run("leaves a user-supplied client untouched", func(t *testing.T) {
+		transport := &http.Transport{}
+		httpClient := &http.Client{Transport: transport}
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(httpClient), WithMinTLSVersion(tls.VersionTLS13)); err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if transport.TLSClientConfig != nil {
+			t.Error("Expected the user-supplied transport not to be modified")
+		}
+	})
+
+	t.Run("rejects unknown versions", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithMinTLSVersion(0)); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestWithDialContext(t *testing.T) {
+	t.Run("opens connections with the custom dialer", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`))
+		}))
+		defer server.Close()
+
+		var dialed []string
+		dialer := &net.Dialer{}
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithBaseURL("http://api.payjp.internal"),
+			WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialed = append(dialed, addr)
+				return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+			}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(dialed) != 1 || dialed[0] != "api.payjp.internal:80" {
+			t.Errorf("Dialed addresses incorrect. Got: %v, Expected: [api.payjp.internal:80]", dialed)
+		}
+	})
+
+	t.Run("leaves a user-supplied client untouched", func(t *testing.T) {
+		transport := &http.Transport{}
+		dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+			t.Error("Unexpected dial")
+			return nil, nil
+		}
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithHTTPClient(&http.Client{Transport: transport}), WithDialContext(dial)); err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if transport.DialContext != nil {
+			t.Error("Expected the user-supplied transport not to be modified")
+		}
+	})
+
+	t.Run("rejects a nil dialer", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithDialContext(nil)); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestWithURLRewriter(t *testing.T) {
+	t.Run("rewrites the final request URL", func(t *testing.T) {
+		var captured *http.Request
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				captured = req
+				return jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`), nil
+			})}),
+			WithBaseURL("https://api.pay.jp"),
+			WithURLRewriter(func(u *url.URL) { u.Path = "/proxy" + u.Path }),
+			WithURLRewriter(func(u *url.URL) { u.Host = "gateway.internal" }),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		limit := 10
+		if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), &GetAllCustomersParams{Limit: &limit})); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if captured.URL.String() != "https://gateway.internal/proxy/v2/customers?limit=10" {
+			t.Errorf("URL incorrect. Got: %s, Expected: https://gateway.internal/proxy/v2/customers?limit=10", captured.URL)
+		}
+		if captured.Host != "gateway.internal" {
+			t.Errorf("Host incorrect. Got: %s, Expected: gateway.internal", captured.Host)
+		}
+	})
+
+	t.Run("rejects a nil rewriter", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithURLRewriter(nil)); err == nil {
+			t.Error("Expected error for nil rewriter")
+		}
+	})
+}
+
+func TestWithRequestStartHeader(t *testing.T) {
+	clock := newFakeClock()
+	var stamps []string
+	client, err := NewPayjpClientWithResponses("sk_test_key",
+		WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			stamps = append(stamps, req.Header.Get(RequestStartHeader))
+			if len(stamps) == 1 {
+				return jsonResponse(503, `{"status":503,"title":"Service Unavailable","type":"about:blank"}`), nil
+			}
+			return jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`), nil
+		})}),
+		WithClock(clock),
+		WithRetry(1, time.Second),
+		WithRetryPolicy(RetryPolicy{Jitter: 0}),
+		WithRequestStartHeader(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	start := clock.Now()
+	if _, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(stamps) != 2 {
+		t.Fatalf("Request count incorrect. Got: %d, Expected: 2", len(stamps))
+	}
+	for i, expected := range []time.Time{start, start.Add(time.Second)} {
+		micros, ok := strings.CutPrefix(stamps[i], "t=")
+		if !ok {
+			t.Fatalf("Header format incorrect. Got: %s, Expected: t=<microseconds>", stamps[i])
+		}
+		n, err := strconv.ParseInt(micros, 10, 64)
+		if err != nil {
+			t.Fatalf("Failed to parse timestamp %s: %v", stamps[i], err)
+		}
+		if got := time.UnixMicro(n); !got.Equal(expected) {
+			t.Errorf("Timestamp of attempt %d incorrect. Got: %s, Expected: %s", i+1, got, expected)
+		}
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	// slowTransport answers after delay, or fails when the request context is done first
+	slowTransport := func(delay time.Duration, attempts *int) roundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			*attempts++
+			select {
+			case <-time.After(delay):
+				return jsonResponse(200, `{"object":"list","data":[],"has_more":false,"url":"/v2/customers"}`), nil
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		}
+	}
+
+	t.Run("fires the deadline on a slow response", func(t *testing.T) {
+		var attempts int
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithHTTPClient(&http.Client{Transport: slowTransport(5*time.Second, &attempts)}),
+			WithTimeout(50*time.Millisecond),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		start := time.Now()
+		_, err = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Deadline fired late. Got: %s, Expected: about 50ms", elapsed)
+		}
+	})
+
+	t.Run("bounds the total time of retries", func(t *testing.T) {
+		var attempts int
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithHTTPClient(&http.Client{Transport: slowTransport(5*time.Second, &attempts)}),
+			WithTimeout(50*time.Millisecond),
+			WithRetry(5, 10*time.Millisecond),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		start := time.Now()
+		_, err = client.GetAllCustomersWithResponse(context.Background(), nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Retries outlived the timeout. Got: %s after %d attempts", elapsed, attempts)
+		}
+	})
+
+	t.Run("fires when given before WithHTTPClient", func(t *testing.T) {
+		newClients := map[string]func(opts ...ClientOption) (*ClientWithResponses, error){
+			"NewPayjpClientWithResponses": func(opts ...ClientOption) (*ClientWithResponses, error) {
+				return NewPayjpClientWithResponses("sk_test_key", opts...)
+			},
+			"NewClientWithResponses": func(opts ...ClientOption) (*ClientWithResponses, error) {
+				return NewClientWithResponses(DEFAULT_BASE_URL, opts...)
+			},
+		}
+		for name, newClient := range newClients {
+			t.Run(name, func(t *testing.T) {
+				var attempts int
+				client, err := newClient(
+					WithTimeout(50*time.Millisecond),
+					WithHTTPClient(&http.Client{Transport: slowTransport(5*time.Second, &attempts)}),
+				)
+				if err != nil {
+					t.Fatalf("Failed to create client: %v", err)
+				}
+
+				start := time.Now()
+				_, err = client.GetAllCustomersWithResponse(context.Background(), nil)
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+				}
+				if elapsed := time.Since(start); elapsed > time.Second {
+					t.Errorf("Deadline fired late. Got: %s, Expected: about 50ms", elapsed)
+				}
+			})
+		}
+	})
+
+	t.Run("leaves fast responses readable", func(t *testing.T) {
+		var attempts int
+		client, err := NewPayjpClientWithResponses("sk_test_key",
+			WithTimeout(time.Second),
+			WithHTTPClient(&http.Client{Transport: slowTransport(0, &attempts)}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		resp, err := Extract(client.GetAllCustomersWithResponse(context.Background(), nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Result == nil {
+			t.Error("Expected a decoded result")
+		}
+		if attempts != 1 {
+			t.Errorf("Attempt count incorrect. Got: %d, Expected: 1", attempts)
+		}
+	})
+
+	t.Run("rejects a non-positive timeout", func(t *testing.T) {
+		if _, err := NewPayjpClientWithResponses("sk_test_key", WithTimeout(0)); err == nil {
+			t.Error("Expected error for zero timeout")
+		}
+	})
+}
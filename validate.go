@@ -0,0 +1,143 @@
+package payjpv2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var (
+	specOnce sync.Once
+	spec     *openapi3.T
+	specErr  error
+)
+
+// loadSpec returns the embedded OpenAPI specification, decoding it once.
+func loadSpec() (*openapi3.T, error) {
+	specOnce.Do(func() {
+		spec, specErr = GetSwagger()
+	})
+	return spec, specErr
+}
+
+// Validate checks a request struct against the constraints of its schema in the embedded
+// OpenAPI specification (required, minimum/maximum, maxLength, pattern, enum, ...) before it
+// is sent. The schema is looked up by the Go type name, e.g. CustomerCreateRequest, and all
+// violations are reported together. Email fields are checked by openapi_types.Email when the
+// request is encoded.
+//
+// Nested request objects that are set but empty, such as a zero BillingDetails, are reported
+// too, since the API rejects them as incomplete objects.
+//
+// Example usage:
+//
+//	req := payjpv2.CustomerCreateRequest{Email: &email}
+//	if err := payjpv2.Validate(req); err != nil {
+//	    return err
+//	}
+func Validate(v any) error {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return errors.New("validate requires a named request type")
+	}
+
+	s, err := loadSpec()
+	if err != nil {
+		return fmt.Errorf("failed to load OpenAPI specification: %w", err)
+	}
+	schemaRef, ok := s.Components.Schemas[t.Name()]
+	if !ok || schemaRef.Value == nil {
+		return fmt.Errorf("no schema found for type %s", t.Name())
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", t.Name(), err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	errs := emptyNestedErrors(reflect.ValueOf(v), "")
+	if err := schemaRef.Value.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		var multiErr openapi3.MultiError
+		if errors.As(err, &multiErr) {
+			errs = append(errs, multiErr...)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid %s: %w", t.Name(), errors.Join(errs...))
+	}
+	return nil
+}
+
+// zeroChecker is implemented by the generated request sub-structs (see zero.gen.go)
+type zeroChecker interface {
+	IsZero() bool
+}
+
+var zeroCheckerType = reflect.TypeOf((*zeroChecker)(nil)).Elem()
+
+// isZeroValue reports whether v is the zero value of its type
+func isZeroValue[T comparable](v T) bool {
+	var zero T
+	return v == zero
+}
+
+// emptyNestedErrors walks the fields of v and reports nested request objects that are set but empty.
+// path is the JSON path of v.
+func emptyNestedErrors(v reflect.Value, path string) []error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.Struct {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		if fv.Type().PkgPath() == t.PkgPath() && fv.Type().Implements(zeroCheckerType) && fv.Interface().(zeroChecker).IsZero() {
+			errs = append(errs, fmt.Errorf("%s is an empty %s", name, fv.Type().Name()))
+			continue
+		}
+		errs = append(errs, emptyNestedErrors(fv, name)...)
+	}
+	return errs
+}
@@ -0,0 +1,88 @@
+package payjpv2
+
+import (
+	"strings"
+	"testing"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("accepts a valid customer create request", func(t *testing.T) {
+		email := openapi_types.Email("customer@example.com")
+		description := "VIP customer"
+		req := CustomerCreateRequest{Email: &email, Description: &description}
+
+		if err := Validate(req); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if err := Validate(&req); err != nil {
+			t.Errorf("Expected no error for pointer, got: %v", err)
+		}
+	})
+
+	t.Run("rejects an invalid email", func(t *testing.T) {
+		email := openapi_types.Email("not-an-email")
+		err := Validate(CustomerCreateRequest{Email: &email})
+		if err == nil {
+			t.Fatal("Expected error for invalid email, got nil")
+		}
+		if !strings.Contains(err.Error(), "invalid CustomerCreateRequest") || !strings.Contains(err.Error(), "email") {
+			t.Errorf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("reports every violation", func(t *testing.T) {
+		id := "invalid id!"
+		description := strings.Repeat("x", 256)
+		err := Validate(CustomerCreateRequest{Id: &id, Description: &description})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		for _, expected := range []string{"invalid CustomerCreateRequest", "pattern", "maximum string length"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Error missing %q: %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("rejects a value below the minimum", func(t *testing.T) {
+		err := Validate(PaymentFlowCreateRequest{Amount: 10, Currency: "jpy"})
+		if err == nil {
+			t.Error("Expected error for amount below minimum, got nil")
+		}
+	})
+
+	t.Run("reports empty nested objects", func(t *testing.T) {
+		req := PaymentMethodCardCreateRequest{
+			Type: "card",
+			Card: PaymentMethodCreateCardDetailsRequest{Number: "4242424242424242", ExpMonth: 12, ExpYear: 2030, Cvc: "123"},
+		}
+		err := Validate(req)
+		if err == nil {
+			t.Fatal("Expected error for empty billing details, got nil")
+		}
+		if !strings.Contains(err.Error(), "billing_details is an empty PaymentMethodCardBillingDetailsRequest") {
+			t.Errorf("Unexpected error message: %s", err.Error())
+		}
+
+		email := "customer@example.com"
+		req.BillingDetails = PaymentMethodCardBillingDetailsRequest{Email: &email, Address: &PaymentMethodBillingAddressRequest{}}
+		err = Validate(req)
+		if err == nil || !strings.Contains(err.Error(), "billing_details.address is an empty PaymentMethodBillingAddressRequest") {
+			t.Errorf("Expected error for empty address, got: %v", err)
+		}
+
+		req.BillingDetails.Address = nil
+		if err := Validate(req); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("rejects unknown types", func(t *testing.T) {
+		type unknownRequest struct{}
+		if err := Validate(unknownRequest{}); err == nil {
+			t.Error("Expected error for type without schema, got nil")
+		}
+	})
+}